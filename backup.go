@@ -4,7 +4,6 @@ package rdb
 // #include "rocksdb/c.h"
 import "C"
 import (
-	"errors"
 	"unsafe"
 )
 
@@ -90,7 +89,7 @@ func OpenBackupEngine(opts *Options, path string) (*BackupEngine, error) {
 	be := C.rocksdb_backup_engine_open(opts.c, cpath, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, newError(C.GoString(cErr))
 	}
 	return &BackupEngine{
 		c:    be,
@@ -111,7 +110,7 @@ func (b *BackupEngine) CreateNewBackup(db *DB) error {
 	C.rocksdb_backup_engine_create_new_backup(b.c, db.c, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 
 	return nil
@@ -139,7 +138,26 @@ func (b *BackupEngine) RestoreDBFromLatestBackup(dbDir, walDir string, ro *Resto
 	C.rocksdb_backup_engine_restore_db_from_latest_backup(b.c, cDbDir, cWalDir, ro.c, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// RestoreDBFromBackup restores the backup with the given id to dbDir. walDir
+// is where the write ahead logs are restored to and usually the same as dbDir.
+func (b *BackupEngine) RestoreDBFromBackup(backupID int64, dbDir, walDir string, ro *RestoreOptions) error {
+	var cErr *C.char
+	cDbDir := C.CString(dbDir)
+	cWalDir := C.CString(walDir)
+	defer func() {
+		C.free(unsafe.Pointer(cDbDir))
+		C.free(unsafe.Pointer(cWalDir))
+	}()
+
+	C.rocksdb_backup_engine_restore_db_from_backup(b.c, cDbDir, cWalDir, ro.c, C.uint32_t(backupID), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -0,0 +1,137 @@
+package rdb
+
+import "encoding/binary"
+
+// BatchBuilder builds a serialized WriteBatch in Go memory, so queueing a
+// record doesn't cross into C. The batch is written with DB.WriteBuilder or
+// converted with WriteBatch.
+//
+// Column families are given by their id, see ColumnFamilyHandle.ID.
+type BatchBuilder struct {
+	data  []byte
+	count uint32
+}
+
+// NewBatchBuilder creates a BatchBuilder with room for reservedBytes of
+// serialized records.
+func NewBatchBuilder(reservedBytes int) *BatchBuilder {
+	return &BatchBuilder{data: make([]byte, writeBatchHeaderSize, writeBatchHeaderSize+reservedBytes)}
+}
+
+// Put queues a key-value pair.
+func (b *BatchBuilder) Put(key, value []byte) {
+	b.PutCF(0, key, value)
+}
+
+// PutCF queues a key-value pair in a column family.
+func (b *BatchBuilder) PutCF(cfID uint32, key, value []byte) {
+	b.appendRecord(WriteBatchRecordTypeValue, WriteBatchRecordTypeColumnFamilyValue, cfID, key, value, true)
+}
+
+// Merge queues a merge of "value" with the existing value of "key".
+func (b *BatchBuilder) Merge(key, value []byte) {
+	b.MergeCF(0, key, value)
+}
+
+// MergeCF queues a merge of "value" with the existing value of "key" in a
+// column family.
+func (b *BatchBuilder) MergeCF(cfID uint32, key, value []byte) {
+	b.appendRecord(WriteBatchRecordTypeMerge, WriteBatchRecordTypeColumnFamilyMerge, cfID, key, value, true)
+}
+
+// Delete queues a deletion of the data at key.
+func (b *BatchBuilder) Delete(key []byte) {
+	b.DeleteCF(0, key)
+}
+
+// DeleteCF queues a deletion of the data at key in a column family.
+func (b *BatchBuilder) DeleteCF(cfID uint32, key []byte) {
+	b.appendRecord(WriteBatchRecordTypeDeletion, WriteBatchRecordTypeColumnFamilyDeletion, cfID, key, nil, false)
+}
+
+// SingleDelete queues a single deletion of the data at key, see
+// DB.SingleDelete.
+func (b *BatchBuilder) SingleDelete(key []byte) {
+	b.SingleDeleteCF(0, key)
+}
+
+// SingleDeleteCF queues a single deletion of the data at key in a column
+// family, see DB.SingleDelete.
+func (b *BatchBuilder) SingleDeleteCF(cfID uint32, key []byte) {
+	b.appendRecord(WriteBatchRecordTypeSingleDeletion, WriteBatchRecordTypeColumnFamilySingleDeletion, cfID, key, nil, false)
+}
+
+// DeleteRange queues a deletion of the data of all the keys in the range
+// [startKey, endKey).
+func (b *BatchBuilder) DeleteRange(startKey, endKey []byte) {
+	b.DeleteRangeCF(0, startKey, endKey)
+}
+
+// DeleteRangeCF queues a deletion of the data of all the keys in the range
+// [startKey, endKey) in a column family.
+func (b *BatchBuilder) DeleteRangeCF(cfID uint32, startKey, endKey []byte) {
+	b.appendRecord(WriteBatchRecordTypeRangeDeletion, WriteBatchRecordTypeColumnFamilyRangeDeletion, cfID, startKey, endKey, true)
+}
+
+// Count returns the number of updates in the batch.
+func (b *BatchBuilder) Count() int {
+	return int(b.count)
+}
+
+// Size returns the size of the serialized batch.
+func (b *BatchBuilder) Size() int {
+	return len(b.data)
+}
+
+// Data returns the serialized batch, it's valid until the builder is
+// changed.
+func (b *BatchBuilder) Data() []byte {
+	binary.LittleEndian.PutUint32(b.data[8:], b.count)
+	return b.data
+}
+
+// WriteBatch returns a WriteBatch with a copy of the records.
+func (b *BatchBuilder) WriteBatch() *WriteBatch {
+	return WriteBatchFrom(b.Data())
+}
+
+// NewIterator returns a iterator to iterate over the records in the batch.
+func (b *BatchBuilder) NewIterator() *WriteBatchIterator {
+	return newWriteBatchIterator(b.Data())
+}
+
+// Reset removes all the enqueued records and keeps the allocated memory.
+func (b *BatchBuilder) Reset() {
+	b.data = b.data[:writeBatchHeaderSize]
+	b.count = 0
+}
+
+// appendRecord appends a record with the tag of the default column family or
+// of the other ones.
+func (b *BatchBuilder) appendRecord(tag, cfTag WriteBatchRecordType, cfID uint32, key, value []byte, hasValue bool) {
+	if cfID == 0 {
+		b.data = append(b.data, byte(tag))
+	} else {
+		b.data = append(b.data, byte(cfTag))
+		b.appendVarint(uint64(cfID))
+	}
+	b.appendSlice(key)
+	if hasValue {
+		b.appendSlice(value)
+	}
+	b.count++
+}
+
+// appendSlice appends a length prefixed slice.
+func (b *BatchBuilder) appendSlice(s []byte) {
+	b.appendVarint(uint64(len(s)))
+	b.data = append(b.data, s...)
+}
+
+func (b *BatchBuilder) appendVarint(x uint64) {
+	for x >= 0x80 {
+		b.data = append(b.data, byte(x)|0x80)
+		x >>= 7
+	}
+	b.data = append(b.data, byte(x))
+}
@@ -0,0 +1,59 @@
+package rdb
+
+import (
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestBatchBuilder(t *testing.T) {
+	db := newTestDB(t, "TestBatchBuilder", nil)
+	defer db.Close()
+	cf, err := db.CreateColumnFamily(NewDefaultOptions(), "guide")
+	ensure.Nil(t, err)
+	defer cf.Destroy()
+	ensure.DeepEqual(t, cf.ID(), uint32(1))
+
+	wo := NewDefaultWriteOptions()
+	ro := NewDefaultReadOptions()
+	ensure.Nil(t, db.Put(wo, []byte("key2"), []byte("val2")))
+
+	b := NewBatchBuilder(64)
+	b.Put([]byte("key1"), []byte("val1"))
+	b.Delete([]byte("key2"))
+	b.PutCF(cf.ID(), []byte("key3"), []byte("val3"))
+	b.SingleDelete([]byte("key4"))
+	b.DeleteRangeCF(cf.ID(), []byte("key5"), []byte("key6"))
+	ensure.DeepEqual(t, b.Count(), 5)
+
+	// the builder serializes like a WriteBatch
+	wb := NewWriteBatch()
+	defer wb.Destroy()
+	wb.Put([]byte("key1"), []byte("val1"))
+	wb.Delete([]byte("key2"))
+	wb.PutCF(cf, []byte("key3"), []byte("val3"))
+	wb.SingleDelete([]byte("key4"))
+	wb.DeleteRangeCF(cf, []byte("key5"), []byte("key6"))
+	ensure.DeepEqual(t, b.Data(), wb.Data())
+	ensure.DeepEqual(t, b.Size(), len(wb.Data()))
+
+	converted := b.WriteBatch()
+	ensure.DeepEqual(t, converted.Count(), 5)
+	converted.Destroy()
+
+	ensure.Nil(t, db.WriteBuilder(wo, b))
+	v1, err := db.GetBytes(ro, []byte("key1"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v1, []byte("val1"))
+	v2, err := db.GetBytes(ro, []byte("key2"))
+	ensure.Nil(t, err)
+	ensure.True(t, v2 == nil)
+	v3, err := db.GetCF(ro, cf, []byte("key3"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v3.Data(), []byte("val3"))
+	v3.Free()
+
+	b.Reset()
+	ensure.DeepEqual(t, b.Count(), 0)
+	ensure.False(t, b.NewIterator().Next())
+}
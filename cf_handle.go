@@ -20,6 +20,11 @@ func (h *ColumnFamilyHandle) UnsafeGetCFHandler() unsafe.Pointer {
 	return unsafe.Pointer(h.c)
 }
 
+// ID returns the id of the column family.
+func (h *ColumnFamilyHandle) ID() uint32 {
+	return uint32(C.rocksdb_column_family_handle_get_id(h.c))
+}
+
 // Destroy calls the destructor of the underlying column family handle.
 func (h *ColumnFamilyHandle) Destroy() {
 	C.rocksdb_column_family_handle_destroy(h.c)
@@ -152,3 +152,41 @@ func TestColumnFamilyPutGetDelete(t *testing.T) {
 	ensure.Nil(t, err)
 	ensure.DeepEqual(t, actualVal.Size(), 0)
 }
+
+func TestColumnFamilyMultiGet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorocksdb-TestColumnFamilyMultiGet")
+	ensure.Nil(t, err)
+
+	givenNames := []string{"default", "guide"}
+	opts := NewDefaultOptions()
+	opts.SetCreateIfMissingColumnFamilies(true)
+	opts.SetCreateIfMissing(true)
+	db, cfh, err := OpenDbColumnFamilies(opts, dir, givenNames, []*Options{opts, opts})
+	ensure.Nil(t, err)
+	defer db.Close()
+	defer cfh[0].Destroy()
+	defer cfh[1].Destroy()
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	ro := NewDefaultReadOptions()
+	defer ro.Destroy()
+
+	ensure.Nil(t, db.PutCF(wo, cfh[0], []byte("hello0"), []byte("world0")))
+	ensure.Nil(t, db.PutCF(wo, cfh[1], []byte("hello1"), []byte("world1")))
+
+	keys := [][]byte{[]byte("hello0"), []byte("hello1"), []byte("hello1")}
+	values, errs := db.MultiGetCF(ro, []*ColumnFamilyHandle{cfh[0], cfh[1], cfh[0]}, keys)
+	ensure.DeepEqual(t, errs, []error{nil, nil, nil})
+	ensure.DeepEqual(t, values[0], []byte("world0"))
+	ensure.DeepEqual(t, values[1], []byte("world1"))
+	ensure.True(t, values[2] == nil)
+
+	values, errs = db.MultiGetCF(ro, []*ColumnFamilyHandle{cfh[1]}, keys)
+	ensure.DeepEqual(t, errs, []error{nil, nil, nil})
+	ensure.True(t, values[0] == nil)
+	ensure.DeepEqual(t, values[2], []byte("world1"))
+
+	_, errs = db.MultiGetCF(ro, cfh, keys)
+	ensure.NotNil(t, errs[0])
+}
@@ -0,0 +1,111 @@
+package rdb
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// SubscribePollInterval is how often Subscribe looks for new batches in the
+// write-ahead logs once it has caught up.
+var SubscribePollInterval = 100 * time.Millisecond
+
+// ChangeEvent is a write batch read from the write-ahead logs by Subscribe.
+type ChangeEvent struct {
+	// Sequence is the sequence number of the first record of the batch.
+	Sequence uint64
+	// Records are the records of the batch, they are copies and stay valid.
+	Records []WriteBatchRecord
+	// Err is set on the last event when the feed failed, e.g. because the
+	// logs holding the next sequence number were already deleted.
+	Err error
+}
+
+// Subscribe returns a channel receiving the write batches of the database
+// starting with the one holding fromSeq, in order. The write-ahead logs are
+// polled for new batches, so the feed survives the rotation of the logs as
+// long as they are retained until read (see DB.GetUpdatesSince).
+//
+// The first batch may start before fromSeq when fromSeq isn't the first
+// record of a batch. The channel is closed when ctx is done or after an
+// event with Err set.
+func (db *DB) Subscribe(ctx context.Context, fromSeq uint64) <-chan ChangeEvent {
+	ch := make(chan ChangeEvent)
+	go func() {
+		defer close(ch)
+		next := fromSeq
+		for {
+			var err error
+			next, err = db.sendUpdates(ctx, ch, next)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				select {
+				case ch <- ChangeEvent{Sequence: next, Err: err}:
+				case <-ctx.Done():
+				}
+				return
+			}
+			select {
+			case <-time.After(SubscribePollInterval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// sendUpdates sends the batches from next to the last one written and
+// returns the sequence number following them.
+func (db *DB) sendUpdates(ctx context.Context, ch chan<- ChangeEvent, next uint64) (uint64, error) {
+	if db.GetLatestSequenceNumber() < next {
+		return next, nil
+	}
+	iter, err := db.GetUpdatesSince(next)
+	if err != nil {
+		return next, err
+	}
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		batch, seq := iter.GetBatch()
+		end := seq + uint64(batch.Count())
+		if end <= next {
+			batch.Destroy()
+			continue
+		}
+		ev, err := newChangeEvent(batch, seq)
+		batch.Destroy()
+		if err != nil {
+			return next, err
+		}
+		select {
+		case ch <- ev:
+		case <-ctx.Done():
+			return next, ctx.Err()
+		}
+		next = end
+	}
+	err = iter.Err()
+	if errors.Is(err, ErrTryAgain) {
+		// The iterator reached the tail of the live log.
+		err = nil
+	}
+	return next, err
+}
+
+// newChangeEvent copies the records of the batch into a ChangeEvent.
+func newChangeEvent(batch *WriteBatch, seq uint64) (ChangeEvent, error) {
+	ev := ChangeEvent{Sequence: seq}
+	bi := batch.NewIterator()
+	for bi.Next() {
+		r := *bi.Record()
+		r.Key = append([]byte(nil), r.Key...)
+		if r.Value != nil {
+			r.Value = append([]byte(nil), r.Value...)
+		}
+		ev.Records = append(ev.Records, r)
+	}
+	return ev, bi.Error()
+}
@@ -0,0 +1,45 @@
+package rdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+import "C"
+import "unsafe"
+
+// Checkpoint creates openable copies of a live database. Files are hard
+// linked when the copy is on the same filesystem and copied otherwise.
+type Checkpoint struct {
+	c *C.rocksdb_checkpoint_t
+}
+
+// NewCheckpoint creates a Checkpoint object of the database.
+func (db *DB) NewCheckpoint() (*Checkpoint, error) {
+	var cErr *C.char
+	cp := C.rocksdb_checkpoint_object_create(db.c, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, newError(C.GoString(cErr))
+	}
+	return &Checkpoint{c: cp}, nil
+}
+
+// Create creates a consistent copy of the database in dir, which must not
+// exist yet. If the write ahead logs are bigger than logSizeForFlush, the
+// memtables are flushed instead of copying the logs; 0 always flushes.
+func (cp *Checkpoint) Create(dir string, logSizeForFlush uint64) error {
+	var cErr *C.char
+	cDir := C.CString(dir)
+	defer C.free(unsafe.Pointer(cDir))
+
+	C.rocksdb_checkpoint_create(cp.c, cDir, C.uint64_t(logSizeForFlush), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// Destroy deallocates the Checkpoint object.
+func (cp *Checkpoint) Destroy() {
+	C.rocksdb_checkpoint_object_destroy(cp.c)
+	cp.c = nil
+}
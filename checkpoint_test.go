@@ -0,0 +1,43 @@
+package rdb
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestCheckpoint(t *testing.T) {
+	db := newTestDB(t, "TestCheckpoint", nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("val1")))
+
+	dir, err := ioutil.TempDir("", "gorocksdb-TestCheckpoint-copy")
+	ensure.Nil(t, err)
+	defer os.RemoveAll(dir)
+	cpDir := filepath.Join(dir, "checkpoint")
+
+	cp, err := db.NewCheckpoint()
+	ensure.Nil(t, err)
+	defer cp.Destroy()
+	ensure.Nil(t, cp.Create(cpDir, 0))
+	ensure.NotNil(t, cp.Create(cpDir, 0))
+
+	// later writes are not in the checkpoint
+	ensure.Nil(t, db.Put(wo, []byte("key2"), []byte("val2")))
+
+	cpDB, err := OpenDbForReadOnly(NewDefaultOptions(), cpDir, false)
+	ensure.Nil(t, err)
+	defer cpDB.Close()
+	ro := NewDefaultReadOptions()
+	v1, err := cpDB.GetBytes(ro, []byte("key1"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v1, []byte("val1"))
+	v2, err := cpDB.GetBytes(ro, []byte("key2"))
+	ensure.Nil(t, err)
+	ensure.True(t, v2 == nil)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"log"
+
+	"github.com/codegangsta/cli"
+	"github.com/ingn/rdb"
+)
+
+func init() {
+	app.Commands = append(app.Commands, cli.Command{
+		Name:   "checkpoint",
+		Usage:  "create an openable copy of rdb database, hard linked where possible",
+		Action: checkpointDb,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "to",
+				Usage: "checkpoint location, must not exist (required)",
+			},
+			cli.GenericFlag{
+				Name:  "log_size_for_flush",
+				Value: new(bSize),
+				Usage: "copy write ahead logs up to this size instead of flushing the memtables, 0 always flushes",
+			},
+		},
+	})
+}
+
+func checkpointDb(c *cli.Context) error {
+	dbName := c.GlobalString("db")
+	dst := c.String("to")
+	if dbName == "" || dst == "" {
+		cli.ShowCommandHelp(c, "checkpoint")
+		return nil
+	}
+	logSizeForFlush := uint64(*c.Generic("log_size_for_flush").(*bSize))
+
+	dbOptions := rdb.NewDefaultOptions()
+	defaultFlags.setOptions(dbOptions, c)
+
+	db, err := rdb.OpenDb(dbOptions, dbName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	cp, err := db.NewCheckpoint()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer cp.Destroy()
+	if err := cp.Create(dst, logSizeForFlush); err != nil {
+		log.Fatal(err)
+	}
+	log.Println("done")
+	return nil
+}
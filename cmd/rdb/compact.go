@@ -8,11 +8,38 @@ import (
 	"github.com/ingn/rdb"
 )
 
+var bottommostLevelCompactions = map[string]rdb.BottommostLevelCompaction{
+	"skip":            rdb.SkipBottommostLevelCompaction,
+	"if-filter":       rdb.IfHaveCompactionFilterBottommostLevelCompaction,
+	"force":           rdb.ForceBottommostLevelCompaction,
+	"force-optimized": rdb.ForceOptimizedBottommostLevelCompaction,
+}
+
 func init() {
 	app.Commands = append(app.Commands, cli.Command{
 		Name:   "compact",
 		Usage:  "compact rdb database",
 		Action: compactDb,
+		Flags: []cli.Flag{
+			cli.BoolTFlag{
+				Name:  "exclusive",
+				Usage: "no other compaction runs while the manual compaction runs",
+			},
+			cli.BoolFlag{
+				Name:  "change-level",
+				Usage: "move the compacted files to the target level",
+			},
+			cli.IntFlag{
+				Name:  "target-level",
+				Value: -1,
+				Usage: "level the compacted files are moved to with --change-level, -1 for the minimum level holding the data",
+			},
+			cli.StringFlag{
+				Name:  "bottommost",
+				Value: "if-filter",
+				Usage: "bottommost level compaction (skip, if-filter, force, force-optimized)",
+			},
+		},
 	})
 
 }
@@ -23,17 +50,35 @@ func compactDb(c *cli.Context) error {
 		cli.ShowAppHelp(c)
 		return nil
 	}
+	bottommost, ok := bottommostLevelCompactions[c.String("bottommost")]
+	if !ok {
+		log.Fatalf("unknown bottommost level compaction %q", c.String("bottommost"))
+	}
 
 	dbOptions := rdb.NewDefaultOptions()
 	dbOptions.SetCreateIfMissing(true)
 	defaultFlags.setOptions(dbOptions, c)
 
+	compactOptions := rdb.NewDefaultCompactRangeOptions()
+	defer compactOptions.Destroy()
+	compactOptions.SetExclusiveManualCompaction(c.BoolT("exclusive"))
+	compactOptions.SetChangeLevel(c.Bool("change-level"))
+	compactOptions.SetTargetLevel(c.Int("target-level"))
+	compactOptions.SetBottommostLevelCompaction(bottommost)
+
 	db, err := rdb.OpenDb(dbOptions, dbName)
 	if err != nil {
 		log.Fatal(err)
 	}
-	db.CompactRange(rdb.Range{})
-	db.Flush(rdb.NewDefaultFlushOptions())
+	defer db.Close()
+	if err := db.CompactRangeOpt(compactOptions, rdb.Range{}); err != nil {
+		log.Fatal(err)
+	}
+	flushOptions := rdb.NewDefaultFlushOptions()
+	defer flushOptions.Destroy()
+	if err := db.Flush(flushOptions); err != nil {
+		log.Fatal(err)
+	}
 	fmt.Println(db.GetProperty("rocksdb.stats"))
 	fmt.Println("done")
 	return nil
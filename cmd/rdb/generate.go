@@ -84,7 +84,7 @@ func randomData(c *cli.Context) error {
 	woptions.DisableWAL(!c.Bool("wal"))
 	batchSize := c.Int("batchsize")
 
-	batch := rdb.NewWriteBatch()
+	batch := rdb.NewBatchBuilder(batchSize * (len(value) + 32))
 	log.Println("starting...")
 	if c.Bool("stats") {
 		go func() {
@@ -98,12 +98,12 @@ func randomData(c *cli.Context) error {
 		key := hashOf(fmt.Sprintf("%016d", i))
 		batch.Put(key, value)
 		if batch.Count() > batchSize {
-			db.Write(woptions, batch)
-			batch.Clear()
+			db.WriteBuilder(woptions, batch)
+			batch.Reset()
 		}
 		// db.Put(woptions, key, key)
 	}
-	db.Write(woptions, batch)
+	db.WriteBuilder(woptions, batch)
 	db.Flush(rdb.NewDefaultFlushOptions())
 	fmt.Println(db.GetProperty("rocksdb.stats"))
 	return nil
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"github.com/ingn/rdb"
+	"github.com/ingn/rdb/shard"
+)
+
+func init() {
+	app.Commands = append(app.Commands, cli.Command{
+		Name:   "reshard",
+		Usage:  "copy rdb shards into a new set with a different number of shards",
+		Action: reshardDb,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "to",
+				Usage: "destination shards location (required)",
+			},
+			cli.IntFlag{
+				Name:  "shards",
+				Usage: "number of destination shards (required)",
+			},
+			cli.IntFlag{
+				Name:  "batchsize,bs",
+				Value: shard.DefaultReshardBatchSize,
+				Usage: "records copied between two checkpoints",
+			},
+		},
+	})
+}
+
+func reshardDb(c *cli.Context) error {
+	dbName := c.GlobalString("db")
+	dst := c.String("to")
+	shards := c.Int("shards")
+	if dbName == "" || dst == "" || shards <= 0 {
+		cli.ShowCommandHelp(c, "reshard")
+		return nil
+	}
+
+	dbOptions := rdb.NewDefaultOptions()
+	defaultFlags.setOptions(dbOptions, c)
+
+	last := time.Now()
+	log.Println("starting...")
+	err := shard.Reshard(dbName, dst, uint(shards), &shard.ReshardOptions{
+		Options:   dbOptions,
+		BatchSize: c.Int("batchsize"),
+		Progress: func(p shard.ReshardProgress) {
+			if time.Since(last) > time.Second || p.Done == p.Shards {
+				log.Printf("copied %v keys, %v/%v shards done", p.Keys, p.Done, p.Shards)
+				last = time.Now()
+			}
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Println("done")
+	return nil
+}
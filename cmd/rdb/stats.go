@@ -3,16 +3,26 @@ package main
 import (
 	"fmt"
 	"log"
+	"os"
+	"text/tabwriter"
 
 	"github.com/codegangsta/cli"
 	"github.com/ingn/rdb"
+	"github.com/ingn/rdb/shard"
 )
 
 func init() {
 	app.Commands = append(app.Commands, cli.Command{
 		Name:   "stats",
-		Usage:  "print rdb stats",
+		Usage:  "print rdb stats, per shard for shard sets",
 		Action: statsDb,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "property,p",
+				Value: "rocksdb.stats",
+				Usage: "property printed for a single database",
+			},
+		},
 	})
 
 }
@@ -27,11 +37,43 @@ func statsDb(c *cli.Context) error {
 	dbOptions := rdb.NewDefaultOptions()
 	defaultFlags.setOptions(dbOptions, c)
 
+	if shard.GetShardNum(dbName) > 0 {
+		return statsShards(dbOptions, dbName)
+	}
 	db, err := rdb.OpenDbForReadOnly(dbOptions, dbName, false)
 	if err != nil {
 		log.Fatal(err)
 	}
-	fmt.Println(db.GetProperty("rocksdb.stats"))
+	fmt.Println(db.GetProperty(c.String("property")))
 	db.Close()
 	return nil
 }
+
+// statsShards prints the aggregated stats of a shard set and the breakdown
+// per shard. Shard sets of any partitioner are accepted.
+func statsShards(dbOptions *rdb.Options, dbName string) error {
+	st, m, err := shard.ReadStats(dbOptions, dbName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	n := m.Shards
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(w, "property\ttotal\tskew\t")
+	for i := uint(0); i < n; i++ {
+		fmt.Fprintf(w, "%s\t", m.ShardName(i))
+	}
+	fmt.Fprintln(w)
+	for _, name := range shard.StatsProperties {
+		fmt.Fprintf(w, "%s\t%d\t%.2f\t", name, st.Total[name], st.Skew(name))
+		for _, props := range st.Shards {
+			if props == nil {
+				fmt.Fprint(w, "-\t")
+			} else {
+				fmt.Fprintf(w, "%d\t", props[name])
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	return w.Flush()
+}
@@ -2,9 +2,12 @@ package rdb
 
 // #include <stdlib.h>
 // #include "rocksdb/c.h"
+// #include "ext.h"
+// #include "rocksdb_ext.h"
 import "C"
 import (
-	"errors"
+	"bytes"
+	"fmt"
 	"unsafe"
 )
 
@@ -32,7 +35,7 @@ func OpenDb(opts *Options, name string) (*DB, error) {
 	db := C.rocksdb_open(opts.c, cName, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, newError(C.GoString(cErr))
 	}
 	return &DB{
 		name: name,
@@ -51,7 +54,7 @@ func OpenDbForReadOnly(opts *Options, name string, errorIfLogFileExist bool) (*D
 	db := C.rocksdb_open_for_read_only(opts.c, cName, boolToChar(errorIfLogFileExist), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, newError(C.GoString(cErr))
 	}
 	return &DB{
 		name: name,
@@ -69,7 +72,7 @@ func OpenDbColumnFamilies(
 ) (*DB, []*ColumnFamilyHandle, error) {
 	numColumnFamilies := len(cfNames)
 	if numColumnFamilies != len(cfOpts) {
-		return nil, nil, errors.New("must provide the same number of column family names and options")
+		return nil, nil, newError("Invalid argument: must provide the same number of column family names and options")
 	}
 
 	cName := C.CString(name)
@@ -104,7 +107,7 @@ func OpenDbColumnFamilies(
 	)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, nil, errors.New(C.GoString(cErr))
+		return nil, nil, newError(C.GoString(cErr))
 	}
 
 	cfHandles := make([]*ColumnFamilyHandle, numColumnFamilies)
@@ -130,7 +133,7 @@ func OpenDbForReadOnlyColumnFamilies(
 ) (*DB, []*ColumnFamilyHandle, error) {
 	numColumnFamilies := len(cfNames)
 	if numColumnFamilies != len(cfOpts) {
-		return nil, nil, errors.New("must provide the same number of column family names and options")
+		return nil, nil, newError("Invalid argument: must provide the same number of column family names and options")
 	}
 
 	cName := C.CString(name)
@@ -166,7 +169,7 @@ func OpenDbForReadOnlyColumnFamilies(
 	)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, nil, errors.New(C.GoString(cErr))
+		return nil, nil, newError(C.GoString(cErr))
 	}
 
 	cfHandles := make([]*ColumnFamilyHandle, numColumnFamilies)
@@ -192,7 +195,7 @@ func ListColumnFamilies(opts *Options, name string) ([]string, error) {
 	cNames := C.rocksdb_list_column_families(opts.c, cName, &cLen, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, newError(C.GoString(cErr))
 	}
 	namesLen := int(cLen)
 	names := make([]string, namesLen)
@@ -224,7 +227,7 @@ func (db *DB) Get(opts *ReadOptions, key []byte) (*Slice, error) {
 	cValue := C.rocksdb_get(db.c, opts.c, cKey, C.size_t(len(key)), &cValLen, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, newError(C.GoString(cErr))
 	}
 	return NewSlice(cValue, cValLen), nil
 }
@@ -239,7 +242,7 @@ func (db *DB) GetBytes(opts *ReadOptions, key []byte) ([]byte, error) {
 	cValue := C.rocksdb_get(db.c, opts.c, cKey, C.size_t(len(key)), &cValLen, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, newError(C.GoString(cErr))
 	}
 	if cValue == nil {
 		return nil, nil
@@ -258,11 +261,77 @@ func (db *DB) GetCF(opts *ReadOptions, cf *ColumnFamilyHandle, key []byte) (*Sli
 	cValue := C.rocksdb_get_cf(db.c, opts.c, cf.c, cKey, C.size_t(len(key)), &cValLen, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, newError(C.GoString(cErr))
 	}
 	return NewSlice(cValue, cValLen), nil
 }
 
+// MultiGet returns the data associated with the keys from the database,
+// all the keys are looked up with a single call. A missing key has a nil
+// value and a nil error.
+func (db *DB) MultiGet(opts *ReadOptions, keys [][]byte) ([][]byte, []error) {
+	return db.multiGet(opts, nil, keys)
+}
+
+// MultiGetCF is like MultiGet but looks up the keys in column families.
+// Either a single column family is given, which is used for all the keys,
+// or one per key, the key with the same index is looked up in it.
+func (db *DB) MultiGetCF(opts *ReadOptions, cfs []*ColumnFamilyHandle, keys [][]byte) ([][]byte, []error) {
+	if len(cfs) != 1 && len(cfs) != len(keys) {
+		errs := make([]error, len(keys))
+		for i := range errs {
+			errs[i] = newError(fmt.Sprintf("Invalid argument: %d column families given for %d keys", len(cfs), len(keys)))
+		}
+		return make([][]byte, len(keys)), errs
+	}
+	cCFs := make([]*C.rocksdb_column_family_handle_t, len(keys))
+	for i := range cCFs {
+		cCFs[i] = cfs[i%len(cfs)].c
+	}
+	return db.multiGet(opts, cCFs, keys)
+}
+
+func (db *DB) multiGet(opts *ReadOptions, cCFs []*C.rocksdb_column_family_handle_t, keys [][]byte) ([][]byte, []error) {
+	values := make([][]byte, len(keys))
+	errs := make([]error, len(keys))
+	if len(keys) == 0 {
+		return values, errs
+	}
+
+	// cgo forbids passing Go memory holding Go pointers, so the keys are
+	// concatenated into a single buffer passed along with their sizes
+	size := 0
+	for _, key := range keys {
+		size += len(key)
+	}
+	buf := make([]byte, 0, size)
+	cKeySizes := make([]C.size_t, len(keys))
+	for i, key := range keys {
+		buf = append(buf, key...)
+		cKeySizes[i] = C.size_t(len(key))
+	}
+	var cCFsPtr **C.rocksdb_column_family_handle_t
+	if cCFs != nil {
+		cCFsPtr = &cCFs[0]
+	}
+	cValues := make([]*C.char, len(keys))
+	cValueSizes := make([]C.size_t, len(keys))
+	cErrs := make([]*C.char, len(keys))
+
+	C.rocksdb_multi_get_ext(db.c, opts.c, cCFsPtr, C.size_t(len(keys)), byteToChar(buf), &cKeySizes[0],
+		&cValues[0], &cValueSizes[0], &cErrs[0])
+	defer C.rocksdb_multi_get_free_ext(&cValues[0], &cErrs[0], C.size_t(len(keys)))
+
+	for i := range keys {
+		if cErrs[i] != nil {
+			errs[i] = newError(C.GoString(cErrs[i]))
+		} else if cValues[i] != nil {
+			values[i] = C.GoBytes(unsafe.Pointer(cValues[i]), C.int(cValueSizes[i]))
+		}
+	}
+	return values, errs
+}
+
 // Put writes data associated with a key to the database.
 func (db *DB) Put(opts *WriteOptions, key, value []byte) error {
 	var (
@@ -273,7 +342,7 @@ func (db *DB) Put(opts *WriteOptions, key, value []byte) error {
 	C.rocksdb_put(db.c, opts.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -288,7 +357,7 @@ func (db *DB) PutCF(opts *WriteOptions, cf *ColumnFamilyHandle, key, value []byt
 	C.rocksdb_put_cf(db.c, opts.c, cf.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -302,7 +371,7 @@ func (db *DB) Delete(opts *WriteOptions, key []byte) error {
 	C.rocksdb_delete(db.c, opts.c, cKey, C.size_t(len(key)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -316,7 +385,113 @@ func (db *DB) DeleteCF(opts *WriteOptions, cf *ColumnFamilyHandle, key []byte) e
 	C.rocksdb_delete_cf(db.c, opts.c, cf.c, cKey, C.size_t(len(key)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// SingleDelete removes the data associated with the key from the database.
+// Unlike Delete, the tombstone is dropped together with the value it
+// deletes, so it only works for keys which were written once since the
+// previous SingleDelete and never merged. Mixing it with Delete or writing
+// the key multiple times gives undefined results.
+func (db *DB) SingleDelete(opts *WriteOptions, key []byte) error {
+	var (
+		cErr *C.char
+		cKey = byteToChar(key)
+	)
+	C.rocksdb_singledelete(db.c, opts.c, cKey, C.size_t(len(key)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// SingleDeleteCF removes the data associated with the key from the database
+// and column family, see SingleDelete.
+func (db *DB) SingleDeleteCF(opts *WriteOptions, cf *ColumnFamilyHandle, key []byte) error {
+	var (
+		cErr *C.char
+		cKey = byteToChar(key)
+	)
+	C.rocksdb_singledelete_cf(db.c, opts.c, cf.c, cKey, C.size_t(len(key)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// DeleteRange removes the data of all the keys in the range [startKey,
+// endKey) from the database with a single range tombstone.
+func (db *DB) DeleteRange(opts *WriteOptions, startKey, endKey []byte) error {
+	var (
+		cErr      *C.char
+		cStartKey = byteToChar(startKey)
+		cEndKey   = byteToChar(endKey)
+	)
+	C.rocksdb_delete_range_ext(db.c, opts.c, cStartKey, C.size_t(len(startKey)), cEndKey, C.size_t(len(endKey)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// DeleteRangeCF removes the data of all the keys in the range [startKey,
+// endKey) from the database and column family with a single range tombstone.
+func (db *DB) DeleteRangeCF(opts *WriteOptions, cf *ColumnFamilyHandle, startKey, endKey []byte) error {
+	var (
+		cErr      *C.char
+		cStartKey = byteToChar(startKey)
+		cEndKey   = byteToChar(endKey)
+	)
+	C.rocksdb_delete_range_cf(db.c, opts.c, cf.c, cStartKey, C.size_t(len(startKey)), cEndKey, C.size_t(len(endKey)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// DeletePrefix removes the data of all the keys starting with the prefix
+// from the database with a range tombstone. An empty prefix removes all the
+// keys.
+func (db *DB) DeletePrefix(opts *WriteOptions, prefix []byte) error {
+	if end := prefixSuccessor(prefix); end != nil {
+		return db.DeleteRange(opts, prefix, end)
+	}
+	// no key follows the prefix, the range ends with the last key
+	ro := NewDefaultReadOptions()
+	defer ro.Destroy()
+	ro.SetFillCache(false)
+	it := db.NewIterator(ro)
+	defer it.Close()
+	it.SeekToLast()
+	if err := it.Err(); err != nil {
+		return err
+	}
+	if !it.Valid() || bytes.Compare(it.Key(), prefix) < 0 {
+		return nil
+	}
+	last := append([]byte(nil), it.Key()...)
+	wb := NewWriteBatch()
+	defer wb.Destroy()
+	wb.DeleteRange(prefix, last)
+	wb.Delete(last)
+	return db.Write(opts, wb)
+}
+
+// prefixSuccessor returns the smallest key bigger than all the keys starting
+// with the prefix, nil if there is none.
+func prefixSuccessor(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] != 0xff {
+			end := append([]byte(nil), prefix[:i+1]...)
+			end[i]++
+			return end
+		}
 	}
 	return nil
 }
@@ -331,7 +506,7 @@ func (db *DB) Merge(opts *WriteOptions, key []byte, value []byte) error {
 	C.rocksdb_merge(db.c, opts.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -347,7 +522,7 @@ func (db *DB) MergeCF(opts *WriteOptions, cf *ColumnFamilyHandle, key []byte, va
 	C.rocksdb_merge_cf(db.c, opts.c, cf.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -358,7 +533,34 @@ func (db *DB) Write(opts *WriteOptions, batch *WriteBatch) error {
 	C.rocksdb_write(db.c, opts.c, batch.c, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// WriteBuilder writes the batch of a BatchBuilder to the database, the
+// batch is handed over in a single call.
+func (db *DB) WriteBuilder(opts *WriteOptions, b *BatchBuilder) error {
+	var cErr *C.char
+	data := b.Data()
+	C.rocksdb_write_data_ext(db.c, opts.c, byteToChar(data), C.size_t(len(data)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// WriteWithIndex writes a WriteBatchWithIndex to the database. A batch that
+// is also read through NewIteratorWithBase must be created with overwriteKey
+// set, otherwise the iterator returns every record of a key written more
+// than once.
+func (db *DB) WriteWithIndex(opts *WriteOptions, batch *WriteBatchWithIndex) error {
+	var cErr *C.char
+	C.rocksdb_write_writebatch_wi(db.c, opts.c, batch.c, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -383,6 +585,26 @@ func (db *DB) NewSnapshot() *Snapshot {
 	return NewNativeSnapshot(cSnap, db.c)
 }
 
+// GetLatestSequenceNumber returns the sequence number of the most recent
+// write in the database.
+func (db *DB) GetLatestSequenceNumber() uint64 {
+	return uint64(C.rocksdb_get_latest_sequence_number(db.c))
+}
+
+// GetUpdatesSince returns a TransactionLogIterator over the write batches
+// in the write-ahead logs starting with the one holding seq. The logs have
+// to be retained long enough, see Options.SetWALTtlSeconds and
+// Options.SetWalSizeLimitMb.
+func (db *DB) GetUpdatesSince(seq uint64) (*TransactionLogIterator, error) {
+	var cErr *C.char
+	cIter := C.rocksdb_get_updates_since(db.c, C.uint64_t(seq), nil, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, newError(C.GoString(cErr))
+	}
+	return NewNativeTransactionLogIterator(unsafe.Pointer(cIter)), nil
+}
+
 // GetProperty returns the value of a database property.
 func (db *DB) GetProperty(propName string) string {
 	cprop := C.CString(propName)
@@ -411,7 +633,7 @@ func (db *DB) CreateColumnFamily(opts *Options, name string) (*ColumnFamilyHandl
 	cHandle := C.rocksdb_create_column_family(db.c, opts.c, cName, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, newError(C.GoString(cErr))
 	}
 	return NewNativeColumnFamilyHandle(cHandle), nil
 }
@@ -422,7 +644,7 @@ func (db *DB) DropColumnFamily(c *ColumnFamilyHandle) error {
 	C.rocksdb_drop_column_family(db.c, c.c, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -546,13 +768,90 @@ func (db *DB) CompactRangeCF(cf *ColumnFamilyHandle, r Range) {
 	C.rocksdb_compact_range_cf(db.c, cf.c, cStart, C.size_t(len(r.Start)), cLimit, C.size_t(len(r.Limit)))
 }
 
+// CompactRangeOpt runs a manual compaction on the Range of keys given with
+// the compaction options. It blocks until the compaction is done.
+func (db *DB) CompactRangeOpt(opts *CompactRangeOptions, r Range) error {
+	return db.compactRangeOpt(opts, nil, r)
+}
+
+// CompactRangeCFOpt runs a manual compaction on the Range of keys given on
+// the given column family with the compaction options. It blocks until the
+// compaction is done.
+func (db *DB) CompactRangeCFOpt(opts *CompactRangeOptions, cf *ColumnFamilyHandle, r Range) error {
+	return db.compactRangeOpt(opts, cf.c, r)
+}
+
+func (db *DB) compactRangeOpt(opts *CompactRangeOptions, cCF *C.rocksdb_column_family_handle_t, r Range) error {
+	var (
+		cErr   *C.char
+		cStart = byteToChar(r.Start)
+		cLimit = byteToChar(r.Limit)
+	)
+	C.rocksdb_compact_range_opt_ext(db.c, opts.c, cCF, cStart, C.size_t(len(r.Start)), cLimit, C.size_t(len(r.Limit)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// IngestExternalFile atomically loads the SST files created by a
+// SstFileWriter into the database.
+func (db *DB) IngestExternalFile(filePaths []string, opts *IngestExternalFileOptions) error {
+	cFiles := make([]*C.char, len(filePaths))
+	for i, f := range filePaths {
+		cFiles[i] = C.CString(f)
+	}
+	defer func() {
+		for _, f := range cFiles {
+			C.free(unsafe.Pointer(f))
+		}
+	}()
+	if len(cFiles) == 0 {
+		return nil
+	}
+
+	var cErr *C.char
+	C.rocksdb_ingest_external_file(db.c, &cFiles[0], C.size_t(len(cFiles)), opts.c, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// IngestExternalFileCF atomically loads the SST files created by a
+// SstFileWriter into the column family.
+func (db *DB) IngestExternalFileCF(cf *ColumnFamilyHandle, filePaths []string, opts *IngestExternalFileOptions) error {
+	cFiles := make([]*C.char, len(filePaths))
+	for i, f := range filePaths {
+		cFiles[i] = C.CString(f)
+	}
+	defer func() {
+		for _, f := range cFiles {
+			C.free(unsafe.Pointer(f))
+		}
+	}()
+	if len(cFiles) == 0 {
+		return nil
+	}
+
+	var cErr *C.char
+	C.rocksdb_ingest_external_file_cf(db.c, cf.c, &cFiles[0], C.size_t(len(cFiles)), opts.c, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
 // Flush triggers a manuel flush for the database.
 func (db *DB) Flush(opts *FlushOptions) error {
 	var cErr *C.char
 	C.rocksdb_flush(db.c, opts.c, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -563,7 +862,7 @@ func (db *DB) DisableFileDeletions() error {
 	C.rocksdb_disable_file_deletions(db.c, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -574,7 +873,7 @@ func (db *DB) EnableFileDeletions(force bool) error {
 	C.rocksdb_enable_file_deletions(db.c, boolToChar(force), &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -593,6 +892,20 @@ func (db *DB) Close() {
 	C.rocksdb_close(db.c)
 }
 
+// CloseWithError closes the database like Close, but reports the error
+// status of closing it. The database is released even if an error is
+// returned.
+func (db *DB) CloseWithError() error {
+	var cErr *C.char
+	C.rocksdb_close_ext(db.c, &cErr)
+	db.c = nil
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
 // DestroyDb removes a database entirely, removing everything from the
 // filesystem.
 func DestroyDb(name string, opts *Options) error {
@@ -604,7 +917,7 @@ func DestroyDb(name string, opts *Options) error {
 	C.rocksdb_destroy_db(opts.c, cName, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -619,7 +932,7 @@ func RepairDb(name string, opts *Options) error {
 	C.rocksdb_repair_db(opts.c, cName, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
@@ -1,6 +1,7 @@
 package rdb
 
 import (
+	"errors"
 	"io/ioutil"
 	"testing"
 
@@ -76,3 +77,88 @@ func newBenchDB(b *testing.B, name string, applyOpts func(opts *Options)) *DB {
 
 	return db
 }
+
+func TestDBMultiGet(t *testing.T) {
+	db := newTestDB(t, "TestDBMultiGet", nil)
+	defer db.Close()
+
+	var (
+		wo = NewDefaultWriteOptions()
+		ro = NewDefaultReadOptions()
+	)
+	ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("value1")))
+	ensure.Nil(t, db.Put(wo, []byte("key2"), []byte("value2")))
+	ensure.Nil(t, db.Put(wo, []byte("empty"), []byte{}))
+
+	values, errs := db.MultiGet(ro, [][]byte{[]byte("key2"), []byte("missing"), []byte("key1"), []byte("empty")})
+	ensure.DeepEqual(t, errs, []error{nil, nil, nil, nil})
+	ensure.DeepEqual(t, values[0], []byte("value2"))
+	ensure.True(t, values[1] == nil)
+	ensure.DeepEqual(t, values[2], []byte("value1"))
+	ensure.True(t, values[3] != nil && len(values[3]) == 0)
+
+	values, errs = db.MultiGet(ro, nil)
+	ensure.DeepEqual(t, len(values), 0)
+	ensure.DeepEqual(t, len(errs), 0)
+}
+
+func TestDBDeleteRange(t *testing.T) {
+	db := newTestDB(t, "TestDBDeleteRange", nil)
+	defer db.Close()
+
+	var (
+		wo = NewDefaultWriteOptions()
+		ro = NewDefaultReadOptions()
+	)
+	for _, k := range []string{"a1", "a2", "b1", "b2", "c1", "\xff\xff1", "\xff\xff2"} {
+		ensure.Nil(t, db.Put(wo, []byte(k), []byte(k)))
+	}
+	exists := func(k string) bool {
+		v, err := db.GetBytes(ro, []byte(k))
+		ensure.Nil(t, err)
+		return v != nil
+	}
+
+	ensure.Nil(t, db.DeleteRange(wo, []byte("a2"), []byte("b2")))
+	ensure.True(t, exists("a1"))
+	ensure.False(t, exists("a2"))
+	ensure.False(t, exists("b1"))
+	ensure.True(t, exists("b2"))
+
+	ensure.Nil(t, db.DeletePrefix(wo, []byte("b")))
+	ensure.False(t, exists("b2"))
+	ensure.True(t, exists("c1"))
+
+	ensure.Nil(t, db.DeletePrefix(wo, []byte("\xff\xff")))
+	ensure.False(t, exists("\xff\xff1"))
+	ensure.False(t, exists("\xff\xff2"))
+	ensure.True(t, exists("c1"))
+
+	ensure.Nil(t, db.DeletePrefix(wo, nil))
+	ensure.False(t, exists("a1"))
+	ensure.False(t, exists("c1"))
+}
+
+func TestDBCompactRangeOpt(t *testing.T) {
+	db := newTestDB(t, "TestDBCompactRangeOpt", nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	for _, k := range []string{"a", "b", "c"} {
+		ensure.Nil(t, db.Put(wo, []byte(k), []byte(k)))
+		ensure.Nil(t, db.Flush(NewDefaultFlushOptions()))
+	}
+	ensure.DeepEqual(t, db.GetProperty("rocksdb.num-files-at-level0"), "3")
+
+	opts := NewDefaultCompactRangeOptions()
+	defer opts.Destroy()
+	opts.SetChangeLevel(true)
+	opts.SetTargetLevel(2)
+	opts.SetBottommostLevelCompaction(ForceBottommostLevelCompaction)
+	ensure.Nil(t, db.CompactRangeOpt(opts, Range{}))
+	ensure.DeepEqual(t, db.GetProperty("rocksdb.num-files-at-level0"), "0")
+	ensure.DeepEqual(t, db.GetProperty("rocksdb.num-files-at-level2"), "1")
+
+	opts.SetTargetLevel(100)
+	ensure.True(t, errors.Is(db.CompactRangeOpt(opts, Range{}), ErrInvalidArgument))
+}
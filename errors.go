@@ -0,0 +1,95 @@
+package rdb
+
+import (
+	"errors"
+	"strings"
+)
+
+// Errors of the RocksDB status codes. Errors returned by the package wrap
+// them, so the status can be checked with errors.Is.
+var (
+	ErrNotFound        = errors.New("NotFound")
+	ErrCorruption      = errors.New("Corruption")
+	ErrNotSupported    = errors.New("Not implemented")
+	ErrInvalidArgument = errors.New("Invalid argument")
+	ErrIOError         = errors.New("IO error")
+	ErrIncomplete      = errors.New("Result incomplete")
+	ErrTimedOut        = errors.New("Operation timed out")
+	ErrBusy            = errors.New("Resource busy")
+	ErrTryAgain        = errors.New("Operation failed. Try again.")
+)
+
+// Errors of the RocksDB status sub codes, they are matched by errors.Is
+// together with the error of their status code.
+var (
+	// ErrLockTimeout is returned by transactions which timed out waiting
+	// for a lock, it comes with ErrTimedOut.
+	ErrLockTimeout = errors.New("Timeout waiting to lock key")
+	// ErrDeadlock is returned by transactions which were chosen to break a
+	// deadlock, it comes with ErrBusy.
+	ErrDeadlock = errors.New("Deadlock")
+)
+
+// statusSubcodes are the sub codes recognized after the status code prefix.
+var statusSubcodes = []error{
+	ErrLockTimeout,
+	ErrDeadlock,
+}
+
+// statusCodes maps the message prefixes of RocksDB statuses to their errors.
+var statusCodes = []error{
+	ErrNotFound,
+	ErrCorruption,
+	ErrNotSupported,
+	ErrInvalidArgument,
+	ErrIOError,
+	ErrIncomplete,
+	ErrTimedOut,
+	ErrBusy,
+	ErrTryAgain,
+}
+
+// Error is an error status returned by RocksDB.
+type Error struct {
+	// Code is the error of the status code, nil for codes without one.
+	Code error
+	// Subcode is the error of the status sub code, nil for sub codes
+	// without one.
+	Subcode error
+	// Message is the whole status message.
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the error of the status code.
+func (e *Error) Unwrap() error {
+	return e.Code
+}
+
+// Is reports whether the target is the error of the status sub code.
+func (e *Error) Is(target error) bool {
+	return e.Subcode != nil && e.Subcode == target
+}
+
+// newError converts the message of a RocksDB status into an *Error, the
+// status code is recognized by the prefix of the message.
+func newError(msg string) error {
+	for _, code := range statusCodes {
+		prefix := code.Error() + ":"
+		if !strings.HasPrefix(msg, prefix) {
+			continue
+		}
+		e := &Error{Code: code, Message: msg}
+		rest := strings.TrimLeft(msg[len(prefix):], " ")
+		for _, subcode := range statusSubcodes {
+			if strings.HasPrefix(rest, subcode.Error()) {
+				e.Subcode = subcode
+			}
+		}
+		return e
+	}
+	return &Error{Message: msg}
+}
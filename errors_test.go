@@ -0,0 +1,46 @@
+package rdb
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestNewError(t *testing.T) {
+	for msg, code := range map[string]error{
+		"NotFound: ":                            ErrNotFound,
+		"Corruption: block checksum mismatch":   ErrCorruption,
+		"IO error: No space left on device":     ErrIOError,
+		"Resource busy: Deadlock":               ErrBusy,
+		"Operation timed out: Timeout waiting":  ErrTimedOut,
+		"Invalid argument: Column family exist": ErrInvalidArgument,
+		"Not implemented: Not supported":        ErrNotSupported,
+		"Result incomplete: no more ops":        ErrIncomplete,
+	} {
+		err := newError(msg)
+		ensure.DeepEqual(t, err.Error(), msg)
+		ensure.True(t, errors.Is(err, code), msg)
+	}
+	err := newError("Operation timed out: Timeout waiting to lock key")
+	ensure.True(t, errors.Is(err, ErrTimedOut))
+	ensure.True(t, errors.Is(err, ErrLockTimeout))
+	ensure.False(t, errors.Is(err, ErrDeadlock))
+	err = newError("Resource busy: Deadlock")
+	ensure.True(t, errors.Is(err, ErrBusy))
+	ensure.True(t, errors.Is(err, ErrDeadlock))
+	ensure.False(t, errors.Is(newError("Resource busy: "), ErrDeadlock))
+
+	err = newError("something else")
+	ensure.DeepEqual(t, err.(*Error).Code, nil)
+	ensure.False(t, errors.Is(err, ErrNotFound))
+}
+
+func TestOpenMissingDbError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorocksdb-TestOpenMissingDbError")
+	ensure.Nil(t, err)
+
+	_, err = OpenDb(NewDefaultOptions(), dir)
+	ensure.True(t, errors.Is(err, ErrInvalidArgument), err)
+}
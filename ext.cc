@@ -10,7 +10,10 @@
 
 #include "rocksdb/db.h"
 #include "rocksdb/memtablerep.h"
+#include "rocksdb/table.h"
+#include "rocksdb/utilities/transaction.h"
 #include <string>
+#include <cstring>
 #include <iostream>
 
 using namespace rocksdb;
@@ -21,6 +24,12 @@ extern "C" {
 		ReadOptions rep;
 		Slice upper_bound; // stack variable to set pointer to in ReadOptions
 	};
+	struct rocksdb_writeoptions_t    { WriteOptions      rep; };
+	struct rocksdb_compactoptions_t  { CompactRangeOptions rep; };
+	struct rocksdb_column_family_handle_t { ColumnFamilyHandle* rep; };
+	struct rocksdb_block_based_table_options_t { BlockBasedTableOptions rep; };
+	struct rocksdb_snapshot_t        { const Snapshot*   rep; };
+	struct rocksdb_transaction_t     { Transaction*      rep; };
 	// struct rocksdb_options_t         { Options           rep; };
 
 
@@ -34,4 +43,61 @@ extern "C" {
 		rocksdb::ColumnFamilyHandle* cf  = db->rep->DefaultColumnFamily();
 		return db->rep->KeyMayExist(options->rep, cf, Slice(key, keylen), &tmp, nullptr);
 	}
+
+	rocksdb_block_based_table_options_t* rocksdb_block_based_options_create_copy_ext(
+			rocksdb_block_based_table_options_t* options) {
+		return new rocksdb_block_based_table_options_t{options->rep};
+	}
+
+	rocksdb_snapshot_t* rocksdb_transaction_get_snapshot_ext(rocksdb_transaction_t* txn) {
+		const Snapshot* snapshot = txn->rep->GetSnapshot();
+		if (snapshot == nullptr) {
+			return nullptr;
+		}
+		return new rocksdb_snapshot_t{snapshot};
+	}
+
+	void rocksdb_transaction_snapshot_free_ext(rocksdb_snapshot_t* snapshot) {
+		delete snapshot;
+	}
+
+	void rocksdb_close_ext(rocksdb_t* db, char** errptr) {
+		Status s = db->rep->Close();
+		delete db->rep;
+		delete db;
+		if (!s.ok()) {
+			*errptr = strdup(s.ToString().c_str());
+		}
+	}
+
+	void rocksdb_delete_range_ext(
+			rocksdb_t* db,
+			const rocksdb_writeoptions_t* options,
+			const char* start_key, size_t start_key_len,
+			const char* end_key, size_t end_key_len,
+			char** errptr) {
+		Status s = db->rep->DeleteRange(options->rep, db->rep->DefaultColumnFamily(),
+				Slice(start_key, start_key_len), Slice(end_key, end_key_len));
+		if (!s.ok()) {
+			*errptr = strdup(s.ToString().c_str());
+		}
+	}
+
+	void rocksdb_compact_range_opt_ext(
+			rocksdb_t* db,
+			rocksdb_compactoptions_t* opt,
+			rocksdb_column_family_handle_t* column_family,
+			const char* start_key, size_t start_key_len,
+			const char* limit_key, size_t limit_key_len,
+			char** errptr) {
+		Slice a, b;
+		ColumnFamilyHandle* cf = column_family ? column_family->rep : db->rep->DefaultColumnFamily();
+		Status s = db->rep->CompactRange(opt->rep, cf,
+				// Pass nullptr Slice if corresponding "const char*" is nullptr
+				(start_key ? (a = Slice(start_key, start_key_len), &a) : nullptr),
+				(limit_key ? (b = Slice(limit_key, limit_key_len), &b) : nullptr));
+		if (!s.ok()) {
+			*errptr = strdup(s.ToString().c_str());
+		}
+	}
 }
@@ -6,3 +6,27 @@ extern ROCKSDB_LIBRARY_API const char rocksdb_key_may_exist(
 		const rocksdb_readoptions_t* options,
 		const char* key, size_t keylen, 
 		char** errptr);
+
+extern ROCKSDB_LIBRARY_API rocksdb_block_based_table_options_t* rocksdb_block_based_options_create_copy_ext(
+		rocksdb_block_based_table_options_t* options);
+
+// Below returns NULL when the transaction has no snapshot, the handle is
+// freed by rocksdb_transaction_snapshot_free_ext without releasing the snapshot
+extern ROCKSDB_LIBRARY_API rocksdb_snapshot_t* rocksdb_transaction_get_snapshot_ext(rocksdb_transaction_t* txn);
+
+extern ROCKSDB_LIBRARY_API void rocksdb_transaction_snapshot_free_ext(rocksdb_snapshot_t* snapshot);
+
+extern ROCKSDB_LIBRARY_API void rocksdb_close_ext(rocksdb_t* db, char** errptr);
+
+extern ROCKSDB_LIBRARY_API void rocksdb_delete_range_ext(rocksdb_t* db,
+		const rocksdb_writeoptions_t* options,
+		const char* start_key, size_t start_key_len,
+		const char* end_key, size_t end_key_len,
+		char** errptr);
+
+extern ROCKSDB_LIBRARY_API void rocksdb_compact_range_opt_ext(rocksdb_t* db,
+		rocksdb_compactoptions_t* opt,
+		rocksdb_column_family_handle_t* column_family,
+		const char* start_key, size_t start_key_len,
+		const char* limit_key, size_t limit_key_len,
+		char** errptr);
@@ -6,7 +6,6 @@ package rdb
 import "C"
 import (
 	"bytes"
-	"errors"
 	"reflect"
 	"unsafe"
 )
@@ -108,7 +107,7 @@ func (iter *Iterator) Err() error {
 	C.rocksdb_iter_get_error(iter.c, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return newError(C.GoString(cErr))
 	}
 	return nil
 }
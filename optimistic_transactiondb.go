@@ -0,0 +1,159 @@
+package rdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+import "C"
+import (
+	"errors"
+	"math/rand"
+	"time"
+	"unsafe"
+)
+
+// Defaults of RetryOptions.
+const (
+	DefaultTxnMaxRetries = 10
+	DefaultTxnBackoff    = time.Millisecond
+	DefaultTxnMaxBackoff = 100 * time.Millisecond
+)
+
+// NoRetries as RetryOptions.MaxRetries disables the retries of RunInTxn.
+const NoRetries = -1
+
+// OptimisticTransactionDB is a database supporting optimistic transactions,
+// keys are not locked and a Transaction fails to commit with ErrBusy if a
+// key it tracks was written by someone else in the meantime.
+type OptimisticTransactionDB struct {
+	c    *C.rocksdb_optimistictransactiondb_t
+	name string
+	opts *Options
+	base *DB
+}
+
+// OpenOptimisticTransactionDb opens a database supporting optimistic
+// transactions with the specified options.
+func OpenOptimisticTransactionDb(opts *Options, name string) (*OptimisticTransactionDB, error) {
+	var (
+		cErr  *C.char
+		cName = C.CString(name)
+	)
+	defer C.free(unsafe.Pointer(cName))
+	db := C.rocksdb_optimistictransactiondb_open(opts.c, cName, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, newError(C.GoString(cErr))
+	}
+	return &OptimisticTransactionDB{
+		name: name,
+		c:    db,
+		opts: opts,
+		base: &DB{
+			name: name,
+			c:    C.rocksdb_optimistictransactiondb_get_base_db(db),
+			opts: opts,
+		},
+	}, nil
+}
+
+// Name returns the name of the database.
+func (db *OptimisticTransactionDB) Name() string {
+	return db.name
+}
+
+// BaseDB returns the database for reads and writes outside of transactions.
+// It's released by Close and must not be closed.
+func (db *OptimisticTransactionDB) BaseDB() *DB {
+	return db.base
+}
+
+// TransactionBegin begins a new transaction. The oldTxn transaction is
+// reused if given, it has to be committed or rolled back. The snapshots
+// returned by its GetSnapshot are invalid after the reuse.
+//
+// Only the keys written or read by GetForUpdate are checked for conflicts.
+func (db *OptimisticTransactionDB) TransactionBegin(opts *WriteOptions, txnOpts *OptimisticTransactionOptions, oldTxn *Transaction) *Transaction {
+	if oldTxn != nil {
+		oldTxn.freeSnapshots()
+		oldTxn.c = C.rocksdb_optimistictransaction_begin(db.c, opts.c, txnOpts.c, oldTxn.c)
+		return oldTxn
+	}
+	return NewNativeTransaction(C.rocksdb_optimistictransaction_begin(db.c, opts.c, txnOpts.c, nil))
+}
+
+// RetryOptions represent the options of RunInTxn.
+type RetryOptions struct {
+	// MaxRetries is the number of times a conflicting transaction is
+	// retried, NoRetries or any negative value disables retries.
+	// Default: DefaultTxnMaxRetries
+	MaxRetries int
+
+	// Backoff is the initial wait before a retry, it doubles with every
+	// retry up to MaxBackoff. The waits are randomized by up to a half.
+	// Default: DefaultTxnBackoff
+	Backoff time.Duration
+
+	// MaxBackoff is the longest wait before a retry.
+	// Default: DefaultTxnMaxBackoff
+	MaxBackoff time.Duration
+}
+
+// RunInTxn runs fn in a transaction with a snapshot and commits it. If fn or
+// the commit fails with ErrBusy or ErrTryAgain, the transaction is rolled
+// back and fn runs again in a new one after a backoff. Any other error of fn
+// rolls the transaction back and is returned.
+//
+// fn has to read the keys it depends on with GetForUpdate, so they are
+// checked for conflicts.
+func (db *OptimisticTransactionDB) RunInTxn(opts *WriteOptions, retry *RetryOptions, fn func(txn *Transaction) error) error {
+	r := RetryOptions{MaxRetries: DefaultTxnMaxRetries, Backoff: DefaultTxnBackoff, MaxBackoff: DefaultTxnMaxBackoff}
+	if retry != nil {
+		if retry.MaxRetries > 0 {
+			r.MaxRetries = retry.MaxRetries
+		} else if retry.MaxRetries < 0 {
+			r.MaxRetries = 0
+		}
+		if retry.Backoff > 0 {
+			r.Backoff = retry.Backoff
+		}
+		if retry.MaxBackoff > 0 {
+			r.MaxBackoff = retry.MaxBackoff
+		}
+	}
+	txnOpts := NewDefaultOptimisticTransactionOptions()
+	defer txnOpts.Destroy()
+	txnOpts.SetSetSnapshot(true)
+
+	// the transaction is reused by the retries and destroyed even if fn
+	// panics
+	txn := db.TransactionBegin(opts, txnOpts, nil)
+	defer txn.Destroy()
+	backoff := r.Backoff
+	for i := 0; ; i++ {
+		if i > 0 {
+			db.TransactionBegin(opts, txnOpts, txn)
+		}
+		err := fn(txn)
+		if err == nil {
+			err = txn.Commit()
+		}
+		if err == nil {
+			return nil
+		}
+		txn.Rollback()
+		if i >= r.MaxRetries || !(errors.Is(err, ErrBusy) || errors.Is(err, ErrTryAgain)) {
+			return err
+		}
+		time.Sleep(backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1)))
+		if backoff *= 2; backoff > r.MaxBackoff {
+			backoff = r.MaxBackoff
+		}
+	}
+}
+
+// Close closes the database.
+func (db *OptimisticTransactionDB) Close() {
+	C.rocksdb_optimistictransactiondb_close_base_db(db.base.c)
+	C.rocksdb_optimistictransactiondb_close(db.c)
+	db.base.c = nil
+	db.c = nil
+}
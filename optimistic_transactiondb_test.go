@@ -0,0 +1,110 @@
+package rdb
+
+import (
+	"errors"
+	"io/ioutil"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestOptimisticTransactionDBConflict(t *testing.T) {
+	db := newTestOptimisticTransactionDB(t, "TestOptimisticTransactionDBConflict")
+	defer db.Close()
+
+	var (
+		wo      = NewDefaultWriteOptions()
+		ro      = NewDefaultReadOptions()
+		txnOpts = NewDefaultOptimisticTransactionOptions()
+	)
+	txn := db.TransactionBegin(wo, txnOpts, nil)
+	defer txn.Destroy()
+	_, err := txn.GetForUpdate(ro, []byte("key"), true)
+	ensure.Nil(t, err)
+	ensure.Nil(t, txn.Put([]byte("key"), []byte("txn")))
+
+	// the key is not locked, the conflict shows up on commit
+	ensure.Nil(t, db.BaseDB().Put(wo, []byte("key"), []byte("other")))
+	ensure.True(t, errors.Is(txn.Commit(), ErrBusy))
+
+	v, err := db.BaseDB().GetBytes(ro, []byte("key"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v, []byte("other"))
+}
+
+func TestOptimisticTransactionDBRunInTxn(t *testing.T) {
+	db := newTestOptimisticTransactionDB(t, "TestOptimisticTransactionDBRunInTxn")
+	defer db.Close()
+
+	var (
+		wo = NewDefaultWriteOptions()
+		ro = NewDefaultReadOptions()
+	)
+	increment := func(txn *Transaction) error {
+		v, err := txn.GetForUpdate(ro, []byte("counter"), true)
+		if err != nil {
+			return err
+		}
+		defer v.Free()
+		n, _ := strconv.Atoi(string(v.Data()))
+		return txn.Put([]byte("counter"), []byte(strconv.Itoa(n+1)))
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				ensure.Nil(t, db.RunInTxn(wo, &RetryOptions{MaxRetries: 1000}, increment))
+			}
+		}()
+	}
+	wg.Wait()
+	v, err := db.BaseDB().GetBytes(ro, []byte("counter"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, string(v), "100")
+
+	errFn := errors.New("fn failed")
+	ensure.DeepEqual(t, db.RunInTxn(wo, nil, func(txn *Transaction) error {
+		ensure.Nil(t, txn.Put([]byte("counter"), []byte("0")))
+		return errFn
+	}), errFn)
+	v, err = db.BaseDB().GetBytes(ro, []byte("counter"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, string(v), "100")
+
+	// NoRetries runs fn once
+	calls := 0
+	errBusy := &Error{Code: ErrBusy, Message: "Resource busy: "}
+	ensure.DeepEqual(t, db.RunInTxn(wo, &RetryOptions{MaxRetries: NoRetries}, func(txn *Transaction) error {
+		calls++
+		return errBusy
+	}), errBusy)
+	ensure.DeepEqual(t, calls, 1)
+
+	// setting only Backoff keeps the default retries
+	calls = 0
+	ensure.Nil(t, db.RunInTxn(wo, &RetryOptions{Backoff: time.Microsecond}, func(txn *Transaction) error {
+		calls++
+		if calls < 3 {
+			return errBusy
+		}
+		return nil
+	}))
+	ensure.DeepEqual(t, calls, 3)
+}
+
+func newTestOptimisticTransactionDB(t *testing.T, name string) *OptimisticTransactionDB {
+	dir, err := ioutil.TempDir("", "gorocksdb-"+name)
+	ensure.Nil(t, err)
+
+	opts := NewDefaultOptions()
+	opts.SetCreateIfMissing(true)
+	db, err := OpenOptimisticTransactionDb(opts, dir)
+	ensure.Nil(t, err)
+	return db
+}
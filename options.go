@@ -63,6 +63,7 @@ type Options struct {
 	// Hold references for GC.
 	env  *Env
 	bbto *BlockBasedTableOptions
+	wbm  *WriteBufferManager
 
 	// We keep these so we can free their memory in Destroy.
 	ccmp *C.rocksdb_comparator_t
@@ -81,6 +82,18 @@ func NewNativeOptions(c *C.rocksdb_options_t) *Options {
 	return &Options{c: c}
 }
 
+// Clone returns a copy of the options which can be changed without
+// affecting opts. The comparator, merge operator, slice transform and
+// compaction filter are shared with opts, which has to outlive the copy.
+func (opts *Options) Clone() *Options {
+	return &Options{
+		c:    C.rocksdb_options_create_copy(opts.c),
+		env:  opts.env,
+		bbto: opts.bbto,
+		wbm:  opts.wbm,
+	}
+}
+
 // -------------------
 // Parameters that affect behavior
 
@@ -926,6 +939,16 @@ func (opts *Options) SetPlainTableFactory(keyLen uint32, bloomBitsPerKey int, ha
 	C.rocksdb_options_set_plain_table_factory(opts.c, C.uint32_t(keyLen), C.int(bloomBitsPerKey), C.double(hashTableRatio), C.size_t(indexSparseness))
 }
 
+// SetWriteBufferManager sets the manager limiting the memory used by the
+// memtables. Sharing it between databases bounds the memtables of all of
+// them.
+// Default: nil
+func (opts *Options) SetWriteBufferManager(value *WriteBufferManager) {
+	opts.wbm = value
+
+	C.rocksdb_options_set_write_buffer_manager(opts.c, value.c)
+}
+
 // SetCreateIfMissingColumnFamilies specifies whether the column families
 // should be created if they are missing.
 func (opts *Options) SetCreateIfMissingColumnFamilies(value bool) {
@@ -956,6 +979,7 @@ func (opts *Options) Destroy() {
 	opts.c = nil
 	opts.env = nil
 	opts.bbto = nil
+	opts.wbm = nil
 }
 
 // default: true  (as of 5.0.1)
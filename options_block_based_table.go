@@ -2,6 +2,7 @@ package rdb
 
 // #include "rocksdb/c.h"
 // #include "gorocksdb.h"
+// #include "ext.h"
 import "C"
 
 // BlockBasedTableOptions represents block-based table options.
@@ -26,6 +27,16 @@ func NewNativeBlockBasedTableOptions(c *C.rocksdb_block_based_table_options_t) *
 	return &BlockBasedTableOptions{c: c}
 }
 
+// Clone returns a copy of the options which can be changed without
+// affecting opts. The filter policy is shared with opts.
+func (opts *BlockBasedTableOptions) Clone() *BlockBasedTableOptions {
+	return &BlockBasedTableOptions{
+		c:         C.rocksdb_block_based_options_create_copy_ext(opts.c),
+		cache:     opts.cache,
+		compCache: opts.compCache,
+	}
+}
+
 // Destroy deallocates the BlockBasedTableOptions object.
 func (opts *BlockBasedTableOptions) Destroy() {
 	C.rocksdb_block_based_options_destroy(opts.c)
@@ -0,0 +1,73 @@
+package rdb
+
+// #include "rocksdb/c.h"
+import "C"
+
+// BottommostLevelCompaction specifies whether a manual compaction compacts
+// the bottommost level.
+type BottommostLevelCompaction uint
+
+// Bottommost level compaction policies.
+const (
+	// SkipBottommostLevelCompaction skips the bottommost level.
+	SkipBottommostLevelCompaction = BottommostLevelCompaction(0)
+	// IfHaveCompactionFilterBottommostLevelCompaction compacts the
+	// bottommost level only if a compaction filter is set.
+	IfHaveCompactionFilterBottommostLevelCompaction = BottommostLevelCompaction(1)
+	// ForceBottommostLevelCompaction always compacts the bottommost level.
+	ForceBottommostLevelCompaction = BottommostLevelCompaction(2)
+	// ForceOptimizedBottommostLevelCompaction always compacts the bottommost
+	// level, but skips files created by this compaction.
+	ForceOptimizedBottommostLevelCompaction = BottommostLevelCompaction(3)
+)
+
+// CompactRangeOptions represent all of the available options when running a
+// manual compaction.
+type CompactRangeOptions struct {
+	c *C.rocksdb_compactoptions_t
+}
+
+// NewDefaultCompactRangeOptions creates a default CompactRangeOptions object.
+func NewDefaultCompactRangeOptions() *CompactRangeOptions {
+	return NewNativeCompactRangeOptions(C.rocksdb_compactoptions_create())
+}
+
+// NewNativeCompactRangeOptions creates a CompactRangeOptions object.
+func NewNativeCompactRangeOptions(c *C.rocksdb_compactoptions_t) *CompactRangeOptions {
+	return &CompactRangeOptions{c}
+}
+
+// SetExclusiveManualCompaction specify if no other compaction may run while
+// the manual compaction runs.
+// Default: true
+func (opts *CompactRangeOptions) SetExclusiveManualCompaction(value bool) {
+	C.rocksdb_compactoptions_set_exclusive_manual_compaction(opts.c, boolToChar(value))
+}
+
+// SetChangeLevel specify if the compacted files are moved to the minimum
+// level capable of holding the data or to the target level.
+// Default: false
+func (opts *CompactRangeOptions) SetChangeLevel(value bool) {
+	C.rocksdb_compactoptions_set_change_level(opts.c, boolToChar(value))
+}
+
+// SetTargetLevel sets the level the compacted files are moved to when
+// change level is set, -1 means the minimum level capable of holding the
+// data.
+// Default: -1
+func (opts *CompactRangeOptions) SetTargetLevel(value int) {
+	C.rocksdb_compactoptions_set_target_level(opts.c, C.int(value))
+}
+
+// SetBottommostLevelCompaction sets whether the bottommost level is
+// compacted.
+// Default: IfHaveCompactionFilterBottommostLevelCompaction
+func (opts *CompactRangeOptions) SetBottommostLevelCompaction(value BottommostLevelCompaction) {
+	C.rocksdb_compactoptions_set_bottommost_level_compaction(opts.c, C.uchar(value))
+}
+
+// Destroy deallocates the CompactRangeOptions object.
+func (opts *CompactRangeOptions) Destroy() {
+	C.rocksdb_compactoptions_destroy(opts.c)
+	opts.c = nil
+}
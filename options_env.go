@@ -0,0 +1,25 @@
+package rdb
+
+// #include "rocksdb/c.h"
+import "C"
+
+// EnvOptions represent the options of the files read and written by an Env.
+type EnvOptions struct {
+	c *C.rocksdb_envoptions_t
+}
+
+// NewDefaultEnvOptions creates a default EnvOptions object.
+func NewDefaultEnvOptions() *EnvOptions {
+	return NewNativeEnvOptions(C.rocksdb_envoptions_create())
+}
+
+// NewNativeEnvOptions creates a EnvOptions object.
+func NewNativeEnvOptions(c *C.rocksdb_envoptions_t) *EnvOptions {
+	return &EnvOptions{c}
+}
+
+// Destroy deallocates the EnvOptions object.
+func (opts *EnvOptions) Destroy() {
+	C.rocksdb_envoptions_destroy(opts.c)
+	opts.c = nil
+}
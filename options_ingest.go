@@ -0,0 +1,65 @@
+package rdb
+
+// #include "rocksdb/c.h"
+import "C"
+
+// IngestExternalFileOptions represent all of the available options when
+// ingesting external files into the database.
+type IngestExternalFileOptions struct {
+	c *C.rocksdb_ingestexternalfileoptions_t
+}
+
+// NewDefaultIngestExternalFileOptions creates a default
+// IngestExternalFileOptions object.
+func NewDefaultIngestExternalFileOptions() *IngestExternalFileOptions {
+	return NewNativeIngestExternalFileOptions(C.rocksdb_ingestexternalfileoptions_create())
+}
+
+// NewNativeIngestExternalFileOptions creates a IngestExternalFileOptions
+// object.
+func NewNativeIngestExternalFileOptions(c *C.rocksdb_ingestexternalfileoptions_t) *IngestExternalFileOptions {
+	return &IngestExternalFileOptions{c}
+}
+
+// SetMoveFiles specify if the files are moved (hard linked) into the
+// database instead of being copied.
+// Default: false
+func (opts *IngestExternalFileOptions) SetMoveFiles(value bool) {
+	C.rocksdb_ingestexternalfileoptions_set_move_files(opts.c, boolToChar(value))
+}
+
+// SetSnapshotConsistency specify if iterators and snapshots created before
+// the ingestion keep not seeing the ingested keys.
+// Default: true
+func (opts *IngestExternalFileOptions) SetSnapshotConsistency(value bool) {
+	C.rocksdb_ingestexternalfileoptions_set_snapshot_consistency(opts.c, boolToChar(value))
+}
+
+// SetAllowGlobalSeqNo specify if the ingested files may be assigned a
+// sequence number, which is required when they overlap keys in the
+// database. If not allowed, such ingestion fails.
+// Default: true
+func (opts *IngestExternalFileOptions) SetAllowGlobalSeqNo(value bool) {
+	C.rocksdb_ingestexternalfileoptions_set_allow_global_seqno(opts.c, boolToChar(value))
+}
+
+// SetAllowBlockingFlush specify if the memtable may be flushed when it
+// overlaps the ingested files. If not allowed, such ingestion fails.
+// Default: true
+func (opts *IngestExternalFileOptions) SetAllowBlockingFlush(value bool) {
+	C.rocksdb_ingestexternalfileoptions_set_allow_blocking_flush(opts.c, boolToChar(value))
+}
+
+// SetIngestBehind specify if the files are ingested into the bottommost
+// level, below all the existing data, so duplicate keys are skipped. It
+// requires a database opened with allow_ingest_behind.
+// Default: false
+func (opts *IngestExternalFileOptions) SetIngestBehind(value bool) {
+	C.rocksdb_ingestexternalfileoptions_set_ingest_behind(opts.c, boolToChar(value))
+}
+
+// Destroy deallocates the IngestExternalFileOptions object.
+func (opts *IngestExternalFileOptions) Destroy() {
+	C.rocksdb_ingestexternalfileoptions_destroy(opts.c)
+	opts.c = nil
+}
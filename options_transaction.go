@@ -0,0 +1,69 @@
+package rdb
+
+// #include "rocksdb/c.h"
+import "C"
+
+// TransactionOptions represent all of the available options when beginning a
+// Transaction.
+type TransactionOptions struct {
+	c *C.rocksdb_transaction_options_t
+}
+
+// NewDefaultTransactionOptions creates a default TransactionOptions object.
+func NewDefaultTransactionOptions() *TransactionOptions {
+	return NewNativeTransactionOptions(C.rocksdb_transaction_options_create())
+}
+
+// NewNativeTransactionOptions creates a TransactionOptions object.
+func NewNativeTransactionOptions(c *C.rocksdb_transaction_options_t) *TransactionOptions {
+	return &TransactionOptions{c}
+}
+
+// SetSetSnapshot specify if the transaction takes a snapshot when it begins,
+// writes then fail if the keys were changed after the snapshot.
+// Default: false
+func (opts *TransactionOptions) SetSetSnapshot(value bool) {
+	C.rocksdb_transaction_options_set_set_snapshot(opts.c, boolToChar(value))
+}
+
+// SetDeadlockDetect specify if the transaction detects deadlocks while
+// waiting for locks, it then fails with ErrDeadlock.
+// Default: false
+func (opts *TransactionOptions) SetDeadlockDetect(value bool) {
+	C.rocksdb_transaction_options_set_deadlock_detect(opts.c, boolToChar(value))
+}
+
+// SetDeadlockDetectDepth sets the number of waiting transactions followed to
+// detect a deadlock.
+// Default: 50
+func (opts *TransactionOptions) SetDeadlockDetectDepth(value int64) {
+	C.rocksdb_transaction_options_set_deadlock_detect_depth(opts.c, C.int64_t(value))
+}
+
+// SetLockTimeout sets the time in milliseconds the transaction waits for a
+// lock before failing with ErrLockTimeout, -1 means the
+// TransactionDBOptions timeout.
+// Default: -1
+func (opts *TransactionOptions) SetLockTimeout(value int64) {
+	C.rocksdb_transaction_options_set_lock_timeout(opts.c, C.int64_t(value))
+}
+
+// SetExpiration sets the time in milliseconds after which the locks of the
+// transaction may be stolen by other transactions, -1 means never.
+// Default: -1
+func (opts *TransactionOptions) SetExpiration(value int64) {
+	C.rocksdb_transaction_options_set_expiration(opts.c, C.int64_t(value))
+}
+
+// SetMaxWriteBatchSize sets the maximum number of bytes written by the
+// transaction, 0 means no limit.
+// Default: 0
+func (opts *TransactionOptions) SetMaxWriteBatchSize(value uint64) {
+	C.rocksdb_transaction_options_set_max_write_batch_size(opts.c, C.size_t(value))
+}
+
+// Destroy deallocates the TransactionOptions object.
+func (opts *TransactionOptions) Destroy() {
+	C.rocksdb_transaction_options_destroy(opts.c)
+	opts.c = nil
+}
@@ -0,0 +1,55 @@
+package rdb
+
+// #include "rocksdb/c.h"
+import "C"
+
+// TransactionDBOptions represent all of the available options when opening a
+// TransactionDB.
+type TransactionDBOptions struct {
+	c *C.rocksdb_transactiondb_options_t
+}
+
+// NewDefaultTransactionDBOptions creates a default TransactionDBOptions
+// object.
+func NewDefaultTransactionDBOptions() *TransactionDBOptions {
+	return NewNativeTransactionDBOptions(C.rocksdb_transactiondb_options_create())
+}
+
+// NewNativeTransactionDBOptions creates a TransactionDBOptions object.
+func NewNativeTransactionDBOptions(c *C.rocksdb_transactiondb_options_t) *TransactionDBOptions {
+	return &TransactionDBOptions{c}
+}
+
+// SetMaxNumLocks sets the maximum number of keys locked at a time per
+// column family, -1 means no limit.
+// Default: -1
+func (opts *TransactionDBOptions) SetMaxNumLocks(value int64) {
+	C.rocksdb_transactiondb_options_set_max_num_locks(opts.c, C.int64_t(value))
+}
+
+// SetNumStripes sets the number of sub-tables of the lock table per column
+// family, more stripes lower the lock contention.
+// Default: 16
+func (opts *TransactionDBOptions) SetNumStripes(value uint64) {
+	C.rocksdb_transactiondb_options_set_num_stripes(opts.c, C.size_t(value))
+}
+
+// SetTransactionLockTimeout sets the default time in milliseconds a
+// transaction waits for a lock, -1 means no timeout.
+// Default: 1000
+func (opts *TransactionDBOptions) SetTransactionLockTimeout(value int64) {
+	C.rocksdb_transactiondb_options_set_transaction_lock_timeout(opts.c, C.int64_t(value))
+}
+
+// SetDefaultLockTimeout sets the time in milliseconds a write outside of a
+// transaction waits for a lock, -1 means no timeout.
+// Default: 1000
+func (opts *TransactionDBOptions) SetDefaultLockTimeout(value int64) {
+	C.rocksdb_transactiondb_options_set_default_lock_timeout(opts.c, C.int64_t(value))
+}
+
+// Destroy deallocates the TransactionDBOptions object.
+func (opts *TransactionDBOptions) Destroy() {
+	C.rocksdb_transactiondb_options_destroy(opts.c)
+	opts.c = nil
+}
@@ -2,6 +2,7 @@
 
 #include <stdlib.h>
 #include <string>
+#include <vector>
 
 extern "C" {
 	unsigned char rocksdb_iter_seek_to_first_ext(rocksdb_iterator_t* iter) {
@@ -38,4 +39,38 @@ extern "C" {
 		}
 	}
 
+	void rocksdb_write_data_ext(rocksdb_t* db,
+			const rocksdb_writeoptions_t* options,
+			const char* data, size_t len, char** errptr) {
+		rocksdb_writebatch_t* batch = rocksdb_writebatch_create_from(data, len);
+		rocksdb_write(db, options, batch, errptr);
+		rocksdb_writebatch_destroy(batch);
+	}
+
+	void rocksdb_multi_get_ext(rocksdb_t* db,
+			const rocksdb_readoptions_t* options,
+			const rocksdb_column_family_handle_t* const* column_families,
+			size_t num_keys, const char* keys, const size_t* keys_sizes,
+			char** values_list, size_t* values_list_sizes, char** errs) {
+		std::vector<const char*> keys_list(num_keys);
+		for (size_t i = 0; i < num_keys; i++) {
+			keys_list[i] = keys;
+			keys += keys_sizes[i];
+		}
+		if (column_families == NULL) {
+			rocksdb_multi_get(db, options, num_keys, keys_list.data(), keys_sizes,
+					values_list, values_list_sizes, errs);
+		} else {
+			rocksdb_multi_get_cf(db, options, column_families, num_keys, keys_list.data(), keys_sizes,
+					values_list, values_list_sizes, errs);
+		}
+	}
+
+	void rocksdb_multi_get_free_ext(char** values_list, char** errs, size_t num_keys) {
+		for (size_t i = 0; i < num_keys; i++) {
+			free(values_list[i]);
+			free(errs[i]);
+		}
+	}
+
 }
@@ -14,6 +14,15 @@ extern unsigned char rocksdb_iter_seek_ext(rocksdb_iterator_t*, const char* k, s
 extern unsigned char rocksdb_iter_next_ext(rocksdb_iterator_t*);
 extern unsigned char rocksdb_iter_prev_ext(rocksdb_iterator_t*);
 extern void rocksdb_write_ext(rocksdb_t* db, const rocksdb_writeoptions_t* options, rocksdb_writebatch_t* batch, char** errptr);
+// Below write takes a serialized batch, so it's written in a single call
+extern void rocksdb_write_data_ext(rocksdb_t* db, const rocksdb_writeoptions_t* options, const char* data, size_t len, char** errptr);
+
+// Below multi get takes the keys concatenated in a single buffer, column_families may be NULL
+extern void rocksdb_multi_get_ext(rocksdb_t* db, const rocksdb_readoptions_t* options,
+		const rocksdb_column_family_handle_t* const* column_families,
+		size_t num_keys, const char* keys, const size_t* keys_sizes,
+		char** values_list, size_t* values_list_sizes, char** errs);
+extern void rocksdb_multi_get_free_ext(char** values_list, char** errs, size_t num_keys);
 
 #ifdef __cplusplus
 }
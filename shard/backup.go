@@ -0,0 +1,222 @@
+package shard
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ingn/rdb"
+)
+
+// backupFilePrefix prefixes the files describing backup sets in a backup
+// root, the backup id follows.
+const backupFilePrefix = "BACKUP-"
+
+// BackupInfo describes a backup set, one backup of every shard taken at the
+// same time.
+type BackupInfo struct {
+	// ID identifies the backup set in the backup root.
+	ID uint `json:"id"`
+	// Created is the time the backup set was completed.
+	Created time.Time `json:"created"`
+	// Manifest is the manifest of the backed up shards.
+	Manifest Manifest `json:"manifest"`
+	// Backups holds the backup id of every shard in its backup engine.
+	Backups []int64 `json:"backups"`
+}
+
+// Backup creates a backup of every shard in the dir backup root, each shard
+// is backed up by its own rdb.BackupEngine into a subdirectory named like the
+// shard. The backup set gets an id shared by all the shards and is recorded
+// only once every shard is backed up.
+//
+// Writes through the Shard are paused until all the shards are backed up, so
+// the backup set is consistent across shards, reads keep being served.
+// Writes made directly to the databases returned by DBs are not paused. A
+// backup root holds backups of a single shard set.
+func (s *Shard) Backup(dir string) (*BackupInfo, error) {
+	s.writes.Lock()
+	defer s.writes.Unlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	infos, err := ListBackups(dir)
+	if os.IsNotExist(err) {
+		err = os.Mkdir(dir, 0700)
+	}
+	if err != nil {
+		return nil, err
+	}
+	info := &BackupInfo{ID: 1, Manifest: *s.manifest, Backups: make([]int64, len(s.dbs))}
+	if n := len(infos); n > 0 {
+		last := infos[n-1]
+		if !last.Manifest.Created.Equal(s.manifest.Created) || last.Manifest.Shards != s.manifest.Shards {
+			return nil, fmt.Errorf("Backup root %s holds backups of other shards", dir)
+		}
+		info.ID = last.ID + 1
+	}
+
+	err = s.parallel(func(i uint) error {
+		db, err := s.db(i)
+		if err != nil {
+			return err
+		}
+		id, err := backupDB(db, filepath.Join(dir, s.manifest.ShardName(i)))
+		info.Backups[i] = id
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	info.Created = time.Now().UTC()
+	if err := writeJSON(backupFile(dir, info.ID), info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// backupDB backs up the database into the backup engine in dir and returns
+// the id of the new backup.
+func backupDB(db *rdb.DB, dir string) (int64, error) {
+	opts := rdb.NewDefaultOptions()
+	defer opts.Destroy()
+	be, err := rdb.OpenBackupEngine(opts, dir)
+	if err != nil {
+		return 0, err
+	}
+	defer be.Close()
+	if err := be.CreateNewBackup(db); err != nil {
+		return 0, err
+	}
+	info := be.GetInfo()
+	defer info.Destroy()
+	id := int64(0)
+	for i := 0; i < info.GetCount(); i++ {
+		if bid := info.GetBackupId(i); bid > id {
+			id = bid
+		}
+	}
+	return id, nil
+}
+
+// ListBackups returns the complete backup sets in the dir backup root ordered
+// by id.
+func ListBackups(dir string) ([]*BackupInfo, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var infos []*BackupInfo
+	for _, file := range files {
+		if !strings.HasPrefix(file.Name(), backupFilePrefix) || strings.HasSuffix(file.Name(), ".tmp") {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimPrefix(file.Name(), backupFilePrefix), 10, 0)
+		if err != nil {
+			continue
+		}
+		info, err := readBackupInfo(dir, uint(id))
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// Restore restores the backup set id from the backupRoot into a new dst
+// shard set. It refuses backup sets missing the backup of any shard.
+func Restore(backupRoot string, id uint, dst string) error {
+	info, err := readBackupInfo(backupRoot, id)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("Backup %d not found in %s", id, backupRoot)
+	} else if err != nil {
+		return err
+	}
+	m := &info.Manifest
+	if uint(len(info.Backups)) != m.Shards {
+		return fmt.Errorf("Backup %d is incomplete: %d of %d shards", id, len(info.Backups), m.Shards)
+	}
+	if err := checkBackups(backupRoot, info); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(dst); err == nil {
+		return fmt.Errorf("Destination %s already exists", dst)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Mkdir(dst, 0700); err != nil {
+		return err
+	}
+	opts := rdb.NewDefaultOptions()
+	defer opts.Destroy()
+	s := &Shard{name: dst, manifest: m, dbs: make([]*rdb.DB, m.Shards)}
+	err = s.parallel(func(i uint) error {
+		be, err := rdb.OpenBackupEngine(opts, filepath.Join(backupRoot, m.ShardName(i)))
+		if err != nil {
+			return err
+		}
+		defer be.Close()
+		ro := rdb.NewRestoreOptions()
+		defer ro.Destroy()
+		return be.RestoreDBFromBackup(info.Backups[i], s.path(i), s.path(i), ro)
+	})
+	if err == nil {
+		err = WriteManifest(dst, m)
+	}
+	if err != nil {
+		// don't leave a partly restored shard set behind
+		os.RemoveAll(dst)
+		return err
+	}
+	return nil
+}
+
+// checkBackups verifies the backup engine of every shard holds the backup
+// recorded in the backup set.
+func checkBackups(backupRoot string, info *BackupInfo) error {
+	opts := rdb.NewDefaultOptions()
+	defer opts.Destroy()
+	errs := &MultiError{}
+	for i, id := range info.Backups {
+		path := filepath.Join(backupRoot, info.Manifest.ShardName(uint(i)))
+		be, err := rdb.OpenBackupEngine(opts, path)
+		if err != nil {
+			errs.add(uint(i), path, err)
+			continue
+		}
+		found := false
+		bi := be.GetInfo()
+		for j := 0; j < bi.GetCount(); j++ {
+			found = found || bi.GetBackupId(j) == id
+		}
+		bi.Destroy()
+		be.Close()
+		if !found {
+			errs.add(uint(i), path, fmt.Errorf("Backup %d of set %d is missing", id, info.ID))
+		}
+	}
+	return errs.errorOrNil()
+}
+
+func backupFile(dir string, id uint) string {
+	return filepath.Join(dir, fmt.Sprintf("%s%06d", backupFilePrefix, id))
+}
+
+func readBackupInfo(dir string, id uint) (*BackupInfo, error) {
+	data, err := ioutil.ReadFile(backupFile(dir, id))
+	if err != nil {
+		return nil, err
+	}
+	info := &BackupInfo{}
+	if err := json.Unmarshal(data, info); err != nil {
+		return nil, fmt.Errorf("Corrupted backup %s: %v", backupFile(dir, id), err)
+	}
+	return info, nil
+}
@@ -0,0 +1,83 @@
+package shard
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ingn/rdb"
+)
+
+func TestBackupRestore(t *testing.T) {
+	sh, dir := newTestShard(t, 3)
+	defer os.RemoveAll(dir)
+	defer sh.Close()
+	root := tmpLocation()
+	defer os.RemoveAll(root)
+	backups := filepath.Join(root, "backups")
+
+	wo := rdb.NewDefaultWriteOptions()
+	put := func(from, to int) {
+		for i := from; i < to; i++ {
+			key := []byte(fmt.Sprintf("key%03d", i))
+			if err := sh.Put(wo, key, key); err != nil {
+				t.Fatalf("Should not get error: %v", err)
+			}
+		}
+	}
+	put(0, 100)
+	first, err := sh.Backup(backups)
+	if err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	put(100, 200)
+	second, err := sh.Backup(backups)
+	if err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	if first.ID != 1 || second.ID != 2 || len(second.Backups) != 3 {
+		t.Errorf("Wrong backup ids: %v, %v", first.ID, second.ID)
+	}
+	infos, err := ListBackups(backups)
+	if err != nil || len(infos) != 2 {
+		t.Fatalf("Expecting 2 backups, got %v (%v)", len(infos), err)
+	}
+
+	dst := filepath.Join(root, "restored")
+	if err := Restore(backups, first.ID, dst); err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	restored, err := OpenForReadOnly(rdb.NewDefaultOptions(), dst, 3, false)
+	if err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	defer restored.Close()
+	ro := restored.NewReadOptions()
+	defer ro.Destroy()
+	for _, i := range []int{0, 99, 100, 199} {
+		key := []byte(fmt.Sprintf("key%03d", i))
+		v, err := restored.GetBytes(ro, key)
+		if err != nil {
+			t.Fatalf("Should not get error: %v", err)
+		}
+		if (i < 100) != (v != nil) {
+			t.Errorf("Wrong value of %s restored: %q", key, v)
+		}
+	}
+
+	if err := Restore(backups, 3, filepath.Join(root, "missing")); err == nil {
+		t.Errorf("Expecting error for missing backup")
+	}
+	second.ID = 3
+	second.Backups[1] = 42
+	if err := writeJSON(backupFile(backups, second.ID), second); err != nil {
+		t.Fatalf(err.Error())
+	}
+	if err := Restore(backups, 3, filepath.Join(root, "mixed")); err == nil {
+		t.Errorf("Expecting error for incomplete backup")
+	}
+	if _, err := os.Stat(filepath.Join(root, "mixed")); !os.IsNotExist(err) {
+		t.Errorf("Failed restore should leave no destination, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package shard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ShardError is the failure of a single shard.
+type ShardError struct {
+	// Index is the index of the failed shard.
+	Index uint
+	// Path is the directory of the failed shard.
+	Path string
+	// Err is the error returned by the shard.
+	Err error
+}
+
+func (e *ShardError) Error() string {
+	return fmt.Sprintf("shard %d (%s): %v", e.Index, e.Path, e.Err)
+}
+
+// Unwrap returns the error returned by the shard.
+func (e *ShardError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError is returned when an operation spanning several shards fails on
+// some of them. It can be inspected with errors.Is and errors.As, which match
+// both the *ShardError values and the errors returned by the shards.
+type MultiError struct {
+	// Errors holds the failures ordered by shard index.
+	Errors []*ShardError
+}
+
+func (e *MultiError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the failures of the shards.
+func (e *MultiError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+func (e *MultiError) add(i uint, path string, err error) {
+	e.Errors = append(e.Errors, &ShardError{Index: i, Path: path, Err: err})
+}
+
+// errorOrNil returns nil when no shard failed.
+func (e *MultiError) errorOrNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	sort.Slice(e.Errors, func(i, j int) bool { return e.Errors[i].Index < e.Errors[j].Index })
+	return e
+}
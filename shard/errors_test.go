@@ -0,0 +1,49 @@
+package shard
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ingn/rdb"
+)
+
+func TestMultiError(t *testing.T) {
+	errFoo := errors.New("foo")
+	errs := &MultiError{}
+	if errs.errorOrNil() != nil {
+		t.Errorf("Expecting nil error")
+	}
+	errs.add(3, "db/003", errors.New("bar"))
+	errs.add(1, "db/001", errFoo)
+	err := errs.errorOrNil()
+	if !errors.Is(err, errFoo) {
+		t.Errorf("Expecting to match the shard error")
+	}
+	var shErr *ShardError
+	if !errors.As(err, &shErr) || shErr.Index != 1 || shErr.Path != "db/001" {
+		t.Errorf("Wrong first shard error: %+v", shErr)
+	}
+	if msg := err.Error(); msg != "shard 1 (db/001): foo; shard 3 (db/003): bar" {
+		t.Errorf("Wrong message: %v", msg)
+	}
+}
+
+func TestOpenReportsFailedShard(t *testing.T) {
+	sh, dir := newTestShard(t, 3)
+	defer os.RemoveAll(dir)
+	sh.Close()
+
+	if err := os.Remove(filepath.Join(dir, "001", "CURRENT")); err != nil {
+		t.Fatalf(err.Error())
+	}
+	_, err := Open(rdb.NewDefaultOptions(), dir, 3)
+	var shErr *ShardError
+	if !errors.As(err, &shErr) || shErr.Index != 1 {
+		t.Errorf("Expecting error of shard 1, got %v", err)
+	}
+	if len(err.(*MultiError).Errors) != 1 {
+		t.Errorf("Only shard 1 should fail, got %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package shard
+
+import (
+	"errors"
+
+	"github.com/ingn/rdb"
+)
+
+// ErrShardUnavailable is returned by operations routed to a shard which
+// failed to open or was detached. It's wrapped in a *ShardError telling
+// which shard it is.
+var ErrShardUnavailable = errors.New("shard unavailable")
+
+// errDetached is the reason of shards detached by Detach.
+var errDetached = errors.New("detached")
+
+// ShardState describes whether a shard can serve operations.
+type ShardState uint
+
+const (
+	// ShardAvailable shards are open and serve operations.
+	ShardAvailable = ShardState(0)
+	// ShardUnavailable shards failed to open or were detached.
+	ShardUnavailable = ShardState(1)
+)
+
+func (st ShardState) String() string {
+	if st == ShardAvailable {
+		return "available"
+	}
+	return "unavailable"
+}
+
+// ShardHealth reports the state of a single shard.
+type ShardHealth struct {
+	Index uint
+	Path  string
+	State ShardState
+	// Err is the reason an unavailable shard is not open.
+	Err error
+}
+
+// OpenDegraded opens the shards like OpenWithPartitioner, but tolerates
+// shards failing to open as long as at least one shard opens. Failed shards
+// are reported by Health and operations routed to them return
+// ErrShardUnavailable until they are reattached.
+func OpenDegraded(opts *rdb.Options, name string, shardsNum uint, p Partitioner) (*Shard, error) {
+	m, stored, err := prepare(name, shardsNum, p, false)
+	if err != nil {
+		return nil, err
+	}
+	s := &Shard{name: name, manifest: m, partitioner: p}
+	s.openFn = func(path string) (*rdb.DB, error) {
+		return rdb.OpenDb(opts, path)
+	}
+	if err := s.open(true); err != nil {
+		return nil, err
+	}
+	if !stored {
+		if err := WriteManifest(name, m); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Health returns the state of every shard.
+func (s *Shard) Health() []ShardHealth {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	res := make([]ShardHealth, len(s.dbs))
+	for i, db := range s.dbs {
+		res[i] = ShardHealth{Index: uint(i), Path: s.path(uint(i))}
+		if db == nil {
+			res[i].State = ShardUnavailable
+			res[i].Err = s.failures[i]
+		}
+	}
+	return res
+}
+
+// Detach closes the i-th shard and marks it unavailable, so it can be
+// repaired (e.g. with rdb.RepairDb) while the other shards keep serving.
+// Iterators and snapshots of the shard must not be used anymore.
+func (s *Shard) Detach(i uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	db := s.dbs[i]
+	if db == nil {
+		return nil
+	}
+	s.dbs[i], s.failures[i] = nil, errDetached
+	if err := db.CloseWithError(); err != nil {
+		return &ShardError{Index: i, Path: s.path(i), Err: err}
+	}
+	return nil
+}
+
+// Reattach opens the i-th shard again, with the options the shards were
+// opened with, and makes it available.
+func (s *Shard) Reattach(i uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.dbs[i] != nil {
+		return nil
+	}
+	db, err := s.openFn(s.path(i))
+	if err != nil {
+		s.failures[i] = err
+		return &ShardError{Index: i, Path: s.path(i), Err: err}
+	}
+	s.dbs[i], s.failures[i] = db, nil
+	return nil
+}
+
+// db returns the database of the i-th shard or ErrShardUnavailable.
+// The caller has to hold mu.
+func (s *Shard) db(i uint) (*rdb.DB, error) {
+	if s.dbs[i] == nil {
+		return nil, &ShardError{Index: i, Path: s.path(i), Err: ErrShardUnavailable}
+	}
+	return s.dbs[i], nil
+}
+
+// readDB returns the database of the i-th shard to read with opts, or
+// ErrShardUnavailable also when the shard is missing from the snapshot set
+// on opts. The caller has to hold mu.
+func (s *Shard) readDB(i uint, opts *ReadOptions) (*rdb.DB, error) {
+	if opts.noSnapshot != nil && opts.noSnapshot[i] {
+		return nil, &ShardError{Index: i, Path: s.path(i), Err: ErrShardUnavailable}
+	}
+	return s.db(i)
+}
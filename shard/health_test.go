@@ -0,0 +1,86 @@
+package shard
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ingn/rdb"
+)
+
+func TestOpenDegraded(t *testing.T) {
+	sh, dir := newTestShard(t, 3)
+	defer os.RemoveAll(dir)
+	sh.Close()
+
+	current := filepath.Join(dir, "001", "CURRENT")
+	if err := os.Rename(current, current+".bak"); err != nil {
+		t.Fatalf(err.Error())
+	}
+	dbOpts := rdb.NewDefaultOptions()
+	if _, err := Open(dbOpts, dir, 3); err == nil {
+		t.Errorf("Expecting error")
+	}
+	sh, err := OpenDegraded(dbOpts, dir, 3, DefaultPartitioner)
+	if err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	defer sh.Close()
+
+	health := sh.Health()
+	for i, h := range health {
+		if expected := i == 1; expected != (h.State == ShardUnavailable) {
+			t.Errorf("Wrong state of shard %v: %v (%v)", i, h.State, h.Err)
+		}
+	}
+
+	wo := rdb.NewDefaultWriteOptions()
+	var unavailable, available []byte
+	for i := 0; unavailable == nil || available == nil; i++ {
+		key := []byte(fmt.Sprintf("key%d", i))
+		if sh.Index(key) == 1 {
+			unavailable = key
+		} else {
+			available = key
+		}
+	}
+	if err := sh.Put(wo, unavailable, unavailable); !errors.Is(err, ErrShardUnavailable) {
+		t.Errorf("Expecting unavailable shard, got %v", err)
+	}
+	if err := sh.Put(wo, available, available); err != nil {
+		t.Errorf("Should not get error: %v", err)
+	}
+
+	// repair and reattach
+	if err := os.Rename(current+".bak", current); err != nil {
+		t.Fatalf(err.Error())
+	}
+	if err := sh.Reattach(1); err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	if h := sh.Health()[1]; h.State != ShardAvailable {
+		t.Errorf("Shard should be available: %v", h.Err)
+	}
+	if err := sh.Put(wo, unavailable, unavailable); err != nil {
+		t.Errorf("Should not get error: %v", err)
+	}
+
+	if err := sh.Detach(1); err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	ro := sh.NewReadOptions()
+	defer ro.Destroy()
+	if _, err := sh.GetBytes(ro, unavailable); !errors.Is(err, ErrShardUnavailable) {
+		t.Errorf("Expecting unavailable shard, got %v", err)
+	}
+	if db, err := sh.DBFor(unavailable); db != nil || !errors.Is(err, ErrShardUnavailable) {
+		t.Errorf("Expecting unavailable shard, got %v", err)
+	}
+	it := sh.NewIterator(ro)
+	defer it.Close()
+	if err := it.Err(); !errors.Is(err, ErrShardUnavailable) {
+		t.Errorf("Iterator should report unavailable shard, got %v", err)
+	}
+}
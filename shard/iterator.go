@@ -0,0 +1,231 @@
+package shard
+
+import (
+	"bytes"
+	"container/heap"
+
+	"github.com/ingn/rdb"
+)
+
+// Iterator iterates in order over the keys of all the shards by merging
+// one rdb.Iterator per shard. Keys are ordered bytewise, shards opened with
+// a custom comparator are not supported.
+//
+// For example:
+//
+//	it := sh.NewIterator(readOpts)
+//	defer it.Close()
+//
+//	for it.Seek([]byte("foo")); it.ValidForPrefix([]byte("foo")); it.Next() {
+//	    fmt.Printf("Key: %v Value: %v\n", it.Key(), it.Value())
+//	}
+//
+//	if err := it.Err(); err != nil {
+//	    return err
+//	}
+type Iterator struct {
+	iters []*rdb.Iterator
+	heap  iterHeap
+	err   error
+}
+
+// NewIterator returns an Iterator over all the shards that uses the
+// ReadOptions given. Unavailable shards are skipped and reported by Err.
+func (s *Shard) NewIterator(opts *ReadOptions) *Iterator {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	it := &Iterator{}
+	errs := &MultiError{}
+	for i := range s.dbs {
+		db, err := s.readDB(uint(i), opts)
+		if err != nil {
+			errs.Errors = append(errs.Errors, err.(*ShardError))
+			continue
+		}
+		it.iters = append(it.iters, db.NewIterator(opts.opts[i]))
+	}
+	it.err = errs.errorOrNil()
+	return it
+}
+
+// Valid returns false only when an Iterator has iterated past either the
+// first or the last key in the shards.
+func (it *Iterator) Valid() bool {
+	return len(it.heap.iters) > 0
+}
+
+// ValidForPrefix returns false only when an Iterator has iterated past the
+// first or the last key in the shards or the specified prefix.
+func (it *Iterator) ValidForPrefix(prefix []byte) bool {
+	return it.Valid() && bytes.HasPrefix(it.Key(), prefix)
+}
+
+// Key returns the key the iterator currently holds. The key is valid until
+// the iterator is moved.
+func (it *Iterator) Key() []byte {
+	if !it.Valid() {
+		return nil
+	}
+	return it.heap.iters[0].Key()
+}
+
+// Value returns the value the iterator currently holds. The value is valid
+// until the iterator is moved.
+func (it *Iterator) Value() []byte {
+	if !it.Valid() {
+		return nil
+	}
+	return it.heap.iters[0].Value()
+}
+
+// SeekToFirst moves the iterator to the first key in the shards.
+func (it *Iterator) SeekToFirst() {
+	for _, i := range it.iters {
+		i.SeekToFirst()
+	}
+	it.init(false)
+}
+
+// SeekToLast moves the iterator to the last key in the shards.
+func (it *Iterator) SeekToLast() {
+	for _, i := range it.iters {
+		i.SeekToLast()
+	}
+	it.init(true)
+}
+
+// Seek moves the iterator to the position greater than or equal to the key.
+func (it *Iterator) Seek(key []byte) {
+	for _, i := range it.iters {
+		i.Seek(key)
+	}
+	it.init(false)
+}
+
+// Next moves the iterator to the next key in the shards.
+func (it *Iterator) Next() {
+	if !it.Valid() {
+		return
+	}
+	cur := it.heap.iters[0]
+	if it.heap.reverse {
+		// all the other iterators are before the current key,
+		// move them right after it
+		key := append([]byte(nil), cur.Key()...)
+		for _, i := range it.iters {
+			if i == cur {
+				continue
+			}
+			i.Seek(key)
+			if i.Valid() && bytes.Equal(i.Key(), key) {
+				i.Next()
+			}
+		}
+		cur.Next()
+		it.init(false)
+		return
+	}
+	cur.Next()
+	it.fix()
+}
+
+// Prev moves the iterator to the previous key in the shards.
+func (it *Iterator) Prev() {
+	if !it.Valid() {
+		return
+	}
+	cur := it.heap.iters[0]
+	if !it.heap.reverse {
+		// all the other iterators are after the current key,
+		// move them right before it
+		key := append([]byte(nil), cur.Key()...)
+		for _, i := range it.iters {
+			if i == cur {
+				continue
+			}
+			i.Seek(key)
+			if i.Valid() {
+				i.Prev()
+			} else {
+				i.SeekToLast()
+			}
+		}
+		cur.Prev()
+		it.init(true)
+		return
+	}
+	cur.Prev()
+	it.fix()
+}
+
+// Err returns nil if no errors happened during iteration, or the first
+// error of the shard iterators otherwise.
+func (it *Iterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	for _, i := range it.iters {
+		if err := i.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the iterators of all the shards.
+func (it *Iterator) Close() {
+	for _, i := range it.iters {
+		i.Close()
+	}
+	it.iters = nil
+	it.heap.iters = nil
+}
+
+// init rebuilds the heap from the valid shard iterators.
+func (it *Iterator) init(reverse bool) {
+	it.heap.reverse = reverse
+	it.heap.iters = it.heap.iters[:0]
+	for _, i := range it.iters {
+		if i.Valid() {
+			it.heap.iters = append(it.heap.iters, i)
+		}
+	}
+	heap.Init(&it.heap)
+}
+
+// fix restores the heap after its top iterator was moved.
+func (it *Iterator) fix() {
+	if it.heap.iters[0].Valid() {
+		heap.Fix(&it.heap, 0)
+	} else {
+		heap.Pop(&it.heap)
+	}
+}
+
+// iterHeap keeps the shard iterators ordered by their current key, the
+// smallest first or the biggest first when iterating in reverse.
+type iterHeap struct {
+	iters   []*rdb.Iterator
+	reverse bool
+}
+
+func (h iterHeap) Len() int { return len(h.iters) }
+
+func (h iterHeap) Less(i, j int) bool {
+	c := bytes.Compare(h.iters[i].Key(), h.iters[j].Key())
+	if h.reverse {
+		return c > 0
+	}
+	return c < 0
+}
+
+func (h iterHeap) Swap(i, j int) { h.iters[i], h.iters[j] = h.iters[j], h.iters[i] }
+
+func (h *iterHeap) Push(x interface{}) { h.iters = append(h.iters, x.(*rdb.Iterator)) }
+
+func (h *iterHeap) Pop() interface{} {
+	n := len(h.iters)
+	x := h.iters[n-1]
+	h.iters = h.iters[:n-1]
+	return x
+}
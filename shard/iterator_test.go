@@ -0,0 +1,76 @@
+package shard
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/ingn/rdb"
+)
+
+func TestIterator(t *testing.T) {
+	sh, dir := newTestShard(t, 5)
+	defer os.RemoveAll(dir)
+	defer sh.Close()
+
+	wo := rdb.NewDefaultWriteOptions()
+	var givenKeys [][]byte
+	for i := 0; i < 100; i++ {
+		key := []byte(fmt.Sprintf("key%03d", i))
+		givenKeys = append(givenKeys, key)
+		if err := sh.Put(wo, key, key); err != nil {
+			t.Fatalf("Should not get error: %v", err)
+		}
+	}
+
+	ro := sh.NewReadOptions()
+	defer ro.Destroy()
+	it := sh.NewIterator(ro)
+	defer it.Close()
+
+	i := 0
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		if !bytes.Equal(it.Key(), givenKeys[i]) || !bytes.Equal(it.Value(), givenKeys[i]) {
+			t.Fatalf("Wrong pair at %v: %s=%s", i, it.Key(), it.Value())
+		}
+		i++
+	}
+	if i != len(givenKeys) {
+		t.Errorf("Wrong number of keys, expected %v got %v", len(givenKeys), i)
+	}
+
+	i = len(givenKeys) - 1
+	for it.SeekToLast(); it.Valid(); it.Prev() {
+		if !bytes.Equal(it.Key(), givenKeys[i]) {
+			t.Fatalf("Wrong key at %v: %s", i, it.Key())
+		}
+		i--
+	}
+	if i != -1 {
+		t.Errorf("Wrong number of keys in reverse, %v left", i+1)
+	}
+
+	// change direction in the middle of the keyspace
+	it.Seek([]byte("key050"))
+	it.Prev()
+	if !bytes.Equal(it.Key(), []byte("key049")) {
+		t.Errorf("Wrong key after Prev: %s", it.Key())
+	}
+	it.Next()
+	it.Next()
+	if !bytes.Equal(it.Key(), []byte("key051")) {
+		t.Errorf("Wrong key after Next: %s", it.Key())
+	}
+
+	n := 0
+	for it.Seek([]byte("key01")); it.ValidForPrefix([]byte("key01")); it.Next() {
+		n++
+	}
+	if n != 10 {
+		t.Errorf("Wrong number of prefixed keys, expected 10 got %v", n)
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("Should not get error: %v", err)
+	}
+}
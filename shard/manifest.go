@@ -0,0 +1,211 @@
+package shard
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ManifestFile is the name of the file describing a shard set, it's stored
+// next to the shard directories.
+const ManifestFile = "SHARDS"
+
+// DefaultNaming is the fmt format of the shard directory names used for new
+// shard sets.
+const DefaultNaming = "%03d"
+
+// ShardNameFn names the shard directories of shard sets created before the
+// manifest was introduced.
+//
+// Deprecated: shard sets are named by their manifest, ShardNameFn is only
+// used to find the shards of legacy shard sets until their manifest is
+// written, the directory names are recorded in it.
+var ShardNameFn = func(i uint) string { return fmt.Sprintf(DefaultNaming, i) }
+
+// Manifest describes the layout of a shard set. It's written when the shard
+// set is created and validated every time the shards are opened.
+//
+// The shard directories are named by the Naming fmt format, legacy shard
+// sets named by a custom ShardNameFn list them in Names instead.
+type Manifest struct {
+	Shards             uint      `json:"shards"`
+	Naming             string    `json:"naming,omitempty"`
+	Names              []string  `json:"names,omitempty"`
+	Partitioner        string    `json:"partitioner"`
+	PartitionerVersion uint      `json:"partitioner_version"`
+	Created            time.Time `json:"created"`
+}
+
+// NewManifest creates a manifest for a new shard set.
+func NewManifest(shardsNum uint, p Partitioner) *Manifest {
+	return &Manifest{
+		Shards:             shardsNum,
+		Naming:             DefaultNaming,
+		Partitioner:        p.Name(),
+		PartitionerVersion: p.Version(),
+		Created:            time.Now().UTC(),
+	}
+}
+
+// ShardName returns the directory name of the i-th shard.
+func (m *Manifest) ShardName(i uint) string {
+	if m.Naming == "" {
+		return m.Names[i]
+	}
+	return fmt.Sprintf(m.Naming, i)
+}
+
+// Check verifies the shard set can be opened with the given number of shards
+// and partitioner.
+func (m *Manifest) Check(shardsNum uint, p Partitioner) error {
+	if m.Shards != shardsNum {
+		return fmt.Errorf("Wrong number of shards provided (found %v)", m.Shards)
+	}
+	if m.Partitioner != p.Name() || m.PartitionerVersion != p.Version() {
+		return &PartitionerError{
+			Expected:        m.Partitioner,
+			ExpectedVersion: m.PartitionerVersion,
+			Got:             p.Name(),
+			GotVersion:      p.Version(),
+		}
+	}
+	return nil
+}
+
+// PartitionerError is returned when a shard set is opened with a different
+// partitioner than the one it was created with.
+type PartitionerError struct {
+	Expected        string
+	ExpectedVersion uint
+	Got             string
+	GotVersion      uint
+}
+
+func (e *PartitionerError) Error() string {
+	return fmt.Sprintf("Shards were created with partitioner %s (v%v), opened with %s (v%v)",
+		e.Expected, e.ExpectedVersion, e.Got, e.GotVersion)
+}
+
+// ReadManifest reads the manifest of the shard set stored in the name
+// directory.
+func ReadManifest(name string) (*Manifest, error) {
+	data, err := ioutil.ReadFile(filepath.Join(name, ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("Corrupted shards manifest %s: %v", filepath.Join(name, ManifestFile), err)
+	}
+	if m.Shards == 0 || (m.Naming == "" && uint(len(m.Names)) != m.Shards) {
+		return nil, fmt.Errorf("Corrupted shards manifest %s: missing shards layout", filepath.Join(name, ManifestFile))
+	}
+	return m, nil
+}
+
+// WriteManifest atomically replaces the manifest of the shard set stored in
+// the name directory.
+func WriteManifest(name string, m *Manifest) error {
+	return writeJSON(filepath.Join(name, ManifestFile), m)
+}
+
+// readLayout returns the manifest of the shard set stored in the name
+// directory, for shard sets created before the manifest was introduced it's
+// derived from the directory names.
+func readLayout(name string) (*Manifest, error) {
+	m, err := ReadManifest(name)
+	if !os.IsNotExist(err) {
+		return m, err
+	}
+	files, err := ioutil.ReadDir(name)
+	if err != nil {
+		return nil, err
+	}
+	n := legacyShardNum(files)
+	if n == 0 {
+		return nil, fmt.Errorf("No shards found in %s", name)
+	}
+	m = &Manifest{Shards: n}
+	m.setLegacyNaming()
+	return m, nil
+}
+
+// writeJSON atomically replaces the file with v encoded as JSON.
+func writeJSON(file string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return err
+	}
+	tmp := file + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+// prepare returns the manifest the shards in the name directory have to be
+// opened with and whether it's already stored on disk. Shard sets created
+// before the manifest was introduced are recognized by their directory names.
+func prepare(name string, shardsNum uint, p Partitioner, readOnly bool) (*Manifest, bool, error) {
+	if shardsNum == 0 {
+		return nil, false, fmt.Errorf("Number of shards has to be bigger than 0")
+	}
+	m, err := ReadManifest(name)
+	if err == nil {
+		return m, true, m.Check(shardsNum, p)
+	} else if !os.IsNotExist(err) {
+		return nil, false, err
+	}
+	files, err := ioutil.ReadDir(name)
+	if os.IsNotExist(err) && !readOnly { // does not exists, let's create empty
+		return NewManifest(shardsNum, p), false, os.Mkdir(name, 0700)
+	} else if err != nil {
+		return nil, false, err
+	}
+	n := legacyShardNum(files)
+	if n == 0 && readOnly {
+		return nil, false, fmt.Errorf("No shards found in %s", name)
+	}
+	if n != 0 && n != shardsNum {
+		return nil, false, fmt.Errorf("Wrong number of shards provided (found %v)", n)
+	}
+	m = NewManifest(shardsNum, p)
+	if n != 0 {
+		m.setLegacyNaming()
+	}
+	return m, false, nil
+}
+
+// legacyShardNum returns the number of shards named by ShardNameFn.
+func legacyShardNum(files []os.FileInfo) uint {
+	shards := map[string]bool{}
+	for _, file := range files {
+		if file.IsDir() {
+			shards[file.Name()] = true
+		}
+	}
+	i := uint(0)
+	for shards[ShardNameFn(i)] {
+		i++
+	}
+	return i
+}
+
+// setLegacyNaming sets the naming of the shards of a legacy shard set,
+// DefaultNaming unless ShardNameFn names them differently, then the names
+// are listed so they don't depend on ShardNameFn anymore.
+func (m *Manifest) setLegacyNaming() {
+	m.Naming, m.Names = DefaultNaming, nil
+	names := make([]string, m.Shards)
+	custom := false
+	for i := range names {
+		names[i] = ShardNameFn(uint(i))
+		custom = custom || names[i] != fmt.Sprintf(DefaultNaming, i)
+	}
+	if custom {
+		m.Naming, m.Names = "", names
+	}
+}
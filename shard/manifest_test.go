@@ -0,0 +1,113 @@
+package shard
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ingn/rdb"
+)
+
+type testPartitioner struct{ HashPartitioner }
+
+func (testPartitioner) Name() string { return "test" }
+
+func TestManifest(t *testing.T) {
+	sh, dir := newTestShard(t, 3)
+	defer os.RemoveAll(dir)
+	sh.Close()
+
+	m, err := ReadManifest(dir)
+	if err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	if m.Shards != 3 || m.Partitioner != DefaultPartitioner.Name() || m.Naming != DefaultNaming {
+		t.Errorf("Wrong manifest: %+v", m)
+	}
+
+	// stray directories are ignored once the manifest exists
+	if err := os.Mkdir(filepath.Join(dir, "123"), 0700); err != nil {
+		t.Fatalf(err.Error())
+	}
+	dbOpts := rdb.NewDefaultOptions()
+	sh, err = Open(dbOpts, dir, 3)
+	if err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	sh.Close()
+
+	_, err = OpenWithPartitioner(dbOpts, dir, 3, testPartitioner{})
+	if _, ok := err.(*PartitionerError); !ok {
+		t.Errorf("Expecting partitioner error, got %v", err)
+	}
+}
+
+func TestManifestLegacy(t *testing.T) {
+	dir := tmpLocation()
+	defer os.RemoveAll(dir)
+
+	dbOpts := rdb.NewDefaultOptions()
+	dbOpts.SetCreateIfMissing(true)
+	for _, n := range []string{"000", "001"} {
+		db, err := rdb.OpenDb(dbOpts, filepath.Join(dir, n))
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		db.Close()
+	}
+	if n := GetShardNum(dir); n != 2 {
+		t.Errorf("Wrong shards number returned, expected 2 got %v", n)
+	}
+	if _, err := Open(dbOpts, dir, 3); err == nil {
+		t.Errorf("Expecting error")
+	}
+	sh, err := Open(dbOpts, dir, 2)
+	if err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	sh.Close()
+	if _, err := ReadManifest(dir); err != nil {
+		t.Errorf("Manifest should be written for legacy shards: %v", err)
+	}
+}
+
+func TestManifestLegacyShardNameFn(t *testing.T) {
+	dir := tmpLocation()
+	defer os.RemoveAll(dir)
+	defer func(fn func(uint) string) { ShardNameFn = fn }(ShardNameFn)
+	ShardNameFn = func(i uint) string { return fmt.Sprintf("shard-%d", i) }
+
+	dbOpts := rdb.NewDefaultOptions()
+	dbOpts.SetCreateIfMissing(true)
+	for _, n := range []string{"shard-0", "shard-1"} {
+		db, err := rdb.OpenDb(dbOpts, filepath.Join(dir, n))
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+		db.Close()
+	}
+	if n := GetShardNum(dir); n != 2 {
+		t.Errorf("Wrong shards number returned, expected 2 got %v", n)
+	}
+	sh, err := Open(dbOpts, dir, 2)
+	if err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	sh.Close()
+	m, err := ReadManifest(dir)
+	if err != nil || m.Naming != "" || m.ShardName(1) != "shard-1" {
+		t.Errorf("Wrong manifest for legacy shards: %+v (%v)", m, err)
+	}
+
+	// the names are recorded, ShardNameFn isn't needed anymore
+	ShardNameFn = func(i uint) string { return fmt.Sprintf(DefaultNaming, i) }
+	sh, err = Open(dbOpts, dir, 2)
+	if err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	sh.Close()
+	if _, err := os.Stat(filepath.Join(dir, "000")); !os.IsNotExist(err) {
+		t.Errorf("Shards should be opened by their recorded names, got %v", err)
+	}
+}
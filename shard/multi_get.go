@@ -0,0 +1,53 @@
+package shard
+
+import (
+	"sync"
+
+	"github.com/ingn/rdb"
+)
+
+// MultiGet returns the data associated with the keys, in the order of the
+// keys. Keys are grouped by their owning shard and the shards are queried in
+// parallel, each with a single DB.MultiGet. A missing key has a nil value and
+// a nil error, keys of unavailable shards get an error wrapping
+// ErrShardUnavailable.
+func (s *Shard) MultiGet(opts *ReadOptions, keys [][]byte) ([][]byte, []error) {
+	values := make([][]byte, len(keys))
+	errs := make([]error, len(keys))
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	idx := make([][]int, len(s.dbs))
+	for k, key := range keys {
+		i := s.Index(key)
+		idx[i] = append(idx[i], k)
+	}
+
+	wg := sync.WaitGroup{}
+	for i := range idx {
+		if len(idx[i]) == 0 {
+			continue
+		}
+		db, err := s.readDB(uint(i), opts)
+		if err != nil {
+			for _, k := range idx[i] {
+				errs[k] = err
+			}
+			continue
+		}
+		wg.Add(1)
+		go func(i int, db *rdb.DB) {
+			defer wg.Done()
+			shardKeys := make([][]byte, len(idx[i]))
+			for j, k := range idx[i] {
+				shardKeys[j] = keys[k]
+			}
+			vs, es := db.MultiGet(opts.opts[i], shardKeys)
+			for j, k := range idx[i] {
+				values[k], errs[k] = vs[j], es[j]
+			}
+		}(i, db)
+	}
+	wg.Wait()
+	return values, errs
+}
@@ -0,0 +1,58 @@
+package shard
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/ingn/rdb"
+)
+
+func TestMultiGet(t *testing.T) {
+	sh, dir := newTestShard(t, 4)
+	defer os.RemoveAll(dir)
+	defer sh.Close()
+
+	wo := rdb.NewDefaultWriteOptions()
+	keys := make([][]byte, 100)
+	for i := range keys {
+		keys[i] = []byte(fmt.Sprintf("key%03d", i))
+		if i%2 == 0 {
+			if err := sh.Put(wo, keys[i], keys[i]); err != nil {
+				t.Fatalf("Should not get error: %v", err)
+			}
+		}
+	}
+
+	ro := sh.NewReadOptions()
+	defer ro.Destroy()
+	values, errs := sh.MultiGet(ro, keys)
+	if len(values) != len(keys) || len(errs) != len(keys) {
+		t.Fatalf("Wrong number of results %v, %v", len(values), len(errs))
+	}
+	for i, key := range keys {
+		if errs[i] != nil {
+			t.Errorf("Should not get error for %s: %v", key, errs[i])
+		}
+		if i%2 == 0 && string(values[i]) != string(key) {
+			t.Errorf("Wrong value of %s: %q", key, values[i])
+		} else if i%2 == 1 && values[i] != nil {
+			t.Errorf("Missing key %s should be nil, got %q", key, values[i])
+		}
+	}
+
+	if err := sh.Detach(1); err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	values, errs = sh.MultiGet(ro, keys)
+	for i, key := range keys {
+		if sh.Index(key) == 1 {
+			if !errors.Is(errs[i], ErrShardUnavailable) {
+				t.Errorf("Expecting ErrShardUnavailable for %s, got %v", key, errs[i])
+			}
+		} else if i%2 == 0 && string(values[i]) != string(key) {
+			t.Errorf("Wrong value of %s: %q", key, values[i])
+		}
+	}
+}
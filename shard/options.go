@@ -0,0 +1,179 @@
+package shard
+
+import (
+	"errors"
+
+	"github.com/ingn/rdb"
+)
+
+// Options configure the shards together with the resources shared by all of
+// them, so the memory and threads used are bounded regardless of the number
+// of shards.
+type Options struct {
+	// Options are the database options of every shard, the shared resources
+	// are set on a copy of them when the shards are opened.
+	// Default: rdb.NewDefaultOptions()
+	Options *rdb.Options
+
+	// BlockBasedTableOptions are the table options of every shard, the
+	// shared block cache is set on a copy of them.
+	// Default: rdb.NewDefaultBlockBasedTableOptions()
+	BlockBasedTableOptions *rdb.BlockBasedTableOptions
+
+	// Partitioner routes keys to the shards.
+	// Default: DefaultPartitioner
+	Partitioner Partitioner
+
+	// BlockCacheSize is the capacity of the LRU block cache shared by all
+	// the shards. If 0, the block cache of BlockBasedTableOptions is kept.
+	// Default: 0
+	BlockCacheSize int
+
+	// MemtableBudget limits the memory used by the memtables of all the
+	// shards together. If 0, every shard uses its own write buffers.
+	// Default: 0
+	MemtableBudget int
+
+	// ChargeMemtablesToCache charges the memtables to the shared block
+	// cache, so BlockCacheSize bounds the memory of both. It requires
+	// BlockCacheSize.
+	// Default: false
+	ChargeMemtablesToCache bool
+
+	// AllowStall stalls writes once the memtables exceed MemtableBudget
+	// until flushes free enough memory.
+	// Default: false
+	AllowStall bool
+
+	// BackgroundThreads is the number of compaction threads shared by all
+	// the shards. If 0, the thread pool is left untouched.
+	// Default: 0
+	BackgroundThreads int
+
+	// HighPriorityBackgroundThreads is the number of flush threads shared by
+	// all the shards. If 0, the thread pool is left untouched.
+	// Default: 0
+	HighPriorityBackgroundThreads int
+}
+
+// errChargeWithoutCache is returned when memtables are charged to a block
+// cache which isn't configured.
+var errChargeWithoutCache = errors.New("ChargeMemtablesToCache requires BlockCacheSize")
+
+// resources are shared by all the shards and released once they are closed.
+type resources struct {
+	opts  *rdb.Options
+	bbto  *rdb.BlockBasedTableOptions
+	cache *rdb.Cache
+	wbm   *rdb.WriteBufferManager
+	env   *rdb.Env
+}
+
+// build creates the shared resources and sets them on a copy of the
+// database options owned by the resources.
+//
+// The thread pools belong to the default environment, so they are shared
+// with every other database of the process using it.
+func (o *Options) build() (*rdb.Options, *resources, error) {
+	if o.ChargeMemtablesToCache && o.BlockCacheSize <= 0 {
+		return nil, nil, errChargeWithoutCache
+	}
+	res := &resources{}
+	if o.Options != nil {
+		res.opts = o.Options.Clone()
+	} else {
+		res.opts = rdb.NewDefaultOptions()
+	}
+	opts := res.opts
+	if o.BlockCacheSize > 0 {
+		if o.BlockBasedTableOptions != nil {
+			res.bbto = o.BlockBasedTableOptions.Clone()
+		} else {
+			res.bbto = rdb.NewDefaultBlockBasedTableOptions()
+		}
+		res.cache = rdb.NewLRUCache(o.BlockCacheSize)
+		res.bbto.SetBlockCache(res.cache)
+		opts.SetBlockBasedTableFactory(res.bbto)
+	} else if o.BlockBasedTableOptions != nil {
+		opts.SetBlockBasedTableFactory(o.BlockBasedTableOptions)
+	}
+	if o.MemtableBudget > 0 {
+		if o.ChargeMemtablesToCache {
+			res.wbm = rdb.NewWriteBufferManagerWithCache(o.MemtableBudget, res.cache, o.AllowStall)
+		} else {
+			res.wbm = rdb.NewWriteBufferManager(o.MemtableBudget, o.AllowStall)
+		}
+		opts.SetWriteBufferManager(res.wbm)
+	}
+	if o.BackgroundThreads > 0 || o.HighPriorityBackgroundThreads > 0 {
+		res.env = rdb.NewDefaultEnv()
+		if o.BackgroundThreads > 0 {
+			// every shard may use the whole pool, the pool bounds them all
+			res.env.SetBackgroundThreads(o.BackgroundThreads)
+			opts.SetMaxBackgroundCompactions(o.BackgroundThreads)
+		}
+		if o.HighPriorityBackgroundThreads > 0 {
+			res.env.SetHighPriorityBackgroundThreads(o.HighPriorityBackgroundThreads)
+			opts.SetMaxBackgroundFlushes(o.HighPriorityBackgroundThreads)
+		}
+		opts.SetEnv(res.env)
+	}
+	return opts, res, nil
+}
+
+// destroy releases the shared resources, the shards have to be closed.
+func (res *resources) destroy() {
+	res.opts.Destroy()
+	if res.bbto != nil {
+		res.bbto.Destroy()
+	}
+	if res.wbm != nil {
+		res.wbm.Destroy()
+	}
+	if res.cache != nil {
+		res.cache.Destroy()
+	}
+	if res.env != nil {
+		res.env.Destroy()
+	}
+}
+
+func (o *Options) partitioner() Partitioner {
+	if o.Partitioner == nil {
+		return DefaultPartitioner
+	}
+	return o.Partitioner
+}
+
+// OpenWithOptions opens the shards with the resources configured by opts
+// shared between them. The resources are released by Close.
+func OpenWithOptions(opts *Options, name string, shardsNum uint) (*Shard, error) {
+	dbOpts, res, err := opts.build()
+	if err != nil {
+		return nil, err
+	}
+	s, err := OpenWithPartitioner(dbOpts, name, shardsNum, opts.partitioner())
+	if err != nil {
+		res.destroy()
+		return nil, err
+	}
+	s.res = res
+	return s, nil
+}
+
+// OpenForReadOnlyWithOptions opens the shards for readonly usage with the
+// resources configured by opts shared between them. The resources are
+// released by Close.
+func OpenForReadOnlyWithOptions(opts *Options, name string, shardsNum uint, errorIfLogFileExist bool) (*Shard, error) {
+	dbOpts, res, err := opts.build()
+	if err != nil {
+		return nil, err
+	}
+	s, err := OpenForReadOnlyWithPartitioner(dbOpts, name, shardsNum, errorIfLogFileExist, opts.partitioner())
+	if err != nil {
+		res.destroy()
+		return nil, err
+	}
+	s.res = res
+	return s, nil
+}
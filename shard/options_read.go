@@ -0,0 +1,89 @@
+package shard
+
+import (
+	"fmt"
+
+	"github.com/ingn/rdb"
+)
+
+// ReadOptions represent the options used when reading from the shards. They
+// hold one rdb.ReadOptions per shard, so every shard can read from its own
+// part of a Snapshot.
+type ReadOptions struct {
+	opts []*rdb.ReadOptions
+	// noSnapshot marks the shards missing from the snapshot set, they
+	// can't be read consistently.
+	noSnapshot []bool
+}
+
+// NewReadOptions creates default ReadOptions for the shards.
+func (s *Shard) NewReadOptions() *ReadOptions {
+	o := &ReadOptions{}
+	for range s.dbs {
+		o.opts = append(o.opts, rdb.NewDefaultReadOptions())
+	}
+	return o
+}
+
+// Shard returns the read options of the i-th shard.
+func (o *ReadOptions) Shard(i uint) *rdb.ReadOptions {
+	return o.opts[i]
+}
+
+// SetVerifyChecksums speciy if all data read from underlying storage will be
+// verified against corresponding checksums.
+// Default: false
+func (o *ReadOptions) SetVerifyChecksums(value bool) {
+	for _, ro := range o.opts {
+		ro.SetVerifyChecksums(value)
+	}
+}
+
+// SetFillCache specify whether the data read should be cached in memory.
+// Callers may wish to set this field to false for bulk scans.
+// Default: true
+func (o *ReadOptions) SetFillCache(value bool) {
+	for _, ro := range o.opts {
+		ro.SetFillCache(value)
+	}
+}
+
+// SetSnapshot sets the snapshot which should be used for the read.
+// The snapshot must be taken from the same shards and must not have been
+// released, otherwise an error is returned.
+//
+// Shards which were unavailable when the snapshot was taken have no part in
+// it, reads of them fail with ErrShardUnavailable rather than reading
+// outside of the snapshot.
+// Default: nil
+func (o *ReadOptions) SetSnapshot(snap *Snapshot) error {
+	if len(snap.snaps) != len(o.opts) {
+		return fmt.Errorf("Snapshot of %d shards can't be used to read %d shards, it may be released", len(snap.snaps), len(o.opts))
+	}
+	o.noSnapshot = make([]bool, len(o.opts))
+	for i, ro := range o.opts {
+		if snap.snaps[i] != nil {
+			ro.SetSnapshot(snap.snaps[i])
+		} else {
+			o.noSnapshot[i] = true
+		}
+	}
+	return nil
+}
+
+// SetReadTier specify if this read request should process data that ALREADY
+// resides on a particular cache.
+// Default: rdb.ReadAllTier
+func (o *ReadOptions) SetReadTier(value rdb.ReadTier) {
+	for _, ro := range o.opts {
+		ro.SetReadTier(value)
+	}
+}
+
+// Destroy deallocates the read options of all the shards.
+func (o *ReadOptions) Destroy() {
+	for _, ro := range o.opts {
+		ro.Destroy()
+	}
+	o.opts = nil
+}
@@ -0,0 +1,65 @@
+package shard
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/ingn/rdb"
+)
+
+func TestOpenWithOptions(t *testing.T) {
+	dir := tmpLocation()
+	defer os.RemoveAll(dir)
+	dbOpts := rdb.NewDefaultOptions()
+	dbOpts.SetCreateIfMissing(true)
+	opts := &Options{
+		Options:                dbOpts,
+		BlockCacheSize:         8 << 20,
+		MemtableBudget:         4 << 20,
+		ChargeMemtablesToCache: true,
+		BackgroundThreads:      2,
+	}
+	sh, err := OpenWithOptions(opts, dir, 4)
+	if err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	if sh.res.cache == nil || sh.res.wbm == nil || sh.res.env == nil {
+		t.Fatalf("Shared resources not created: %+v", sh.res)
+	}
+
+	wo := rdb.NewDefaultWriteOptions()
+	for i := 0; i < 1000; i++ {
+		key := []byte(fmt.Sprintf("key%04d", i))
+		if err := sh.Put(wo, key, key); err != nil {
+			t.Fatalf("Should not get error: %v", err)
+		}
+	}
+	if usage := sh.res.wbm.MemoryUsage(); usage == 0 || usage > sh.res.wbm.BufferSize() {
+		t.Errorf("Memtables of all shards should be accounted, got %d", usage)
+	}
+	if err := sh.Close(); err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+
+	sh, err = OpenForReadOnlyWithOptions(&Options{BlockCacheSize: 1 << 20}, dir, 4, false)
+	if err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	defer sh.Close()
+	ro := sh.NewReadOptions()
+	defer ro.Destroy()
+	v, err := sh.GetBytes(ro, []byte("key0999"))
+	if err != nil || string(v) != "key0999" {
+		t.Errorf("Wrong value %q (%v)", v, err)
+	}
+}
+
+func TestOpenWithOptionsChargeWithoutCache(t *testing.T) {
+	dir := tmpLocation()
+	defer os.RemoveAll(dir)
+	opts := &Options{MemtableBudget: 4 << 20, ChargeMemtablesToCache: true}
+	if _, err := OpenWithOptions(opts, dir, 2); err != errChargeWithoutCache {
+		t.Errorf("Expecting error for memtables charged without cache, got %v", err)
+	}
+}
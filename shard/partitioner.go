@@ -0,0 +1,38 @@
+package shard
+
+import "hash/fnv"
+
+// Partitioner decides which shard owns a key. Implementations have to be
+// deterministic: the same key and shard count must always map to the same
+// shard, otherwise previously written data becomes unreachable.
+type Partitioner interface {
+	// Partition returns the index of the shard owning the key, it has to be
+	// lower than shardsNum.
+	Partition(key []byte, shardsNum uint) uint
+
+	// Name returns the name of the partitioner.
+	Name() string
+
+	// Version returns the version of the partitioner. Any change in the
+	// placement of keys requires a new version.
+	Version() uint
+}
+
+// DefaultPartitioner is used by Open and OpenForReadOnly.
+var DefaultPartitioner Partitioner = HashPartitioner{}
+
+// HashPartitioner places keys by the 64-bit FNV-1a hash of the whole key.
+type HashPartitioner struct{}
+
+// Partition implements Partitioner.
+func (HashPartitioner) Partition(key []byte, shardsNum uint) uint {
+	h := fnv.New64a()
+	h.Write(key)
+	return uint(h.Sum64() % uint64(shardsNum))
+}
+
+// Name implements Partitioner.
+func (HashPartitioner) Name() string { return "fnv1a64" }
+
+// Version implements Partitioner.
+func (HashPartitioner) Version() uint { return 1 }
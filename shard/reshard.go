@@ -0,0 +1,280 @@
+package shard
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ingn/rdb"
+)
+
+// DefaultReshardBatchSize is the number of records copied between two
+// progress checkpoints when ReshardOptions.BatchSize is not set.
+const DefaultReshardBatchSize = 10000
+
+// reshardStateFile keeps the progress of an unfinished Reshard in the
+// destination directory.
+const reshardStateFile = "RESHARD"
+
+// ReshardOptions represent the options of Reshard.
+type ReshardOptions struct {
+	// Options are used to open both the source and the destination shards,
+	// creating missing databases is enabled on a copy of them.
+	// Default: rdb.NewDefaultOptions()
+	Options *rdb.Options
+
+	// Partitioner routes keys to the destination shards.
+	// Default: DefaultPartitioner
+	Partitioner Partitioner
+
+	// BatchSize is the number of records copied from a source shard between
+	// two progress checkpoints.
+	// Default: DefaultReshardBatchSize
+	BatchSize int
+
+	// Progress, if set, is called after every checkpoint. Calls are
+	// serialized.
+	Progress func(ReshardProgress)
+}
+
+// ReshardProgress reports the progress of Reshard.
+type ReshardProgress struct {
+	// Keys is the number of keys copied by this run.
+	Keys uint64
+	// Done is the number of completely copied source shards.
+	Done uint
+	// Shards is the number of source shards.
+	Shards uint
+}
+
+// reshardState is persisted after every checkpoint so an interrupted
+// Reshard can continue where it stopped.
+type reshardState struct {
+	Source string   `json:"source"`
+	Shards uint     `json:"shards"`
+	Done   []bool   `json:"done"`
+	Last   [][]byte `json:"last"`
+}
+
+// Reshard copies every key of the src shard set into a new dst shard set of
+// newCount shards. Source shards are opened read only and copied in parallel,
+// every key is written to the destination shard chosen by the partitioner.
+//
+// The progress is checkpointed in the destination directory, calling Reshard
+// again with the same arguments after a crash continues from the last
+// checkpoint. An existing dst without a checkpoint is never overwritten.
+func Reshard(src, dst string, newCount uint, opts *ReshardOptions) error {
+	if opts == nil {
+		opts = &ReshardOptions{}
+	}
+	r := &resharder{
+		batchSize: opts.BatchSize,
+		progress:  opts.Progress,
+		file:      filepath.Join(dst, reshardStateFile),
+	}
+	if opts.Options != nil {
+		r.opts = opts.Options.Clone()
+	} else {
+		r.opts = rdb.NewDefaultOptions()
+	}
+	defer r.opts.Destroy()
+	if r.batchSize <= 0 {
+		r.batchSize = DefaultReshardBatchSize
+	}
+	p := opts.Partitioner
+	if p == nil {
+		p = DefaultPartitioner
+	}
+	if newCount == 0 {
+		return fmt.Errorf("Number of shards has to be bigger than 0")
+	}
+
+	m, err := readLayout(src)
+	if err != nil {
+		return err
+	}
+	if err := r.loadState(src, dst, newCount, m.Shards); err != nil {
+		return err
+	}
+
+	r.opts.SetCreateIfMissing(true)
+	r.out, err = OpenWithPartitioner(r.opts, dst, newCount, p)
+	if err != nil {
+		return err
+	}
+	defer r.out.Close()
+	r.wo = rdb.NewDefaultWriteOptions()
+	defer r.wo.Destroy()
+
+	wg := sync.WaitGroup{}
+	errs := &MultiError{}
+	l := sync.Mutex{}
+	for i := uint(0); i < m.Shards; i++ {
+		if r.state.Done[i] {
+			continue
+		}
+		wg.Add(1)
+		go func(i uint) {
+			defer wg.Done()
+			path := filepath.Join(src, m.ShardName(i))
+			if e := r.copyShard(i, path); e != nil {
+				l.Lock()
+				errs.add(i, path, e)
+				l.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	if err := errs.errorOrNil(); err != nil {
+		return err
+	}
+	return os.Remove(r.file)
+}
+
+type resharder struct {
+	opts      *rdb.Options
+	wo        *rdb.WriteOptions
+	out       *Shard
+	batchSize int
+	progress  func(ReshardProgress)
+	file      string
+
+	mu    sync.Mutex
+	state reshardState
+	keys  uint64
+	done  uint
+}
+
+// loadState reads the checkpoint of an interrupted Reshard or starts a new
+// one in an empty destination.
+func (r *resharder) loadState(src, dst string, newCount, srcCount uint) error {
+	source, err := filepath.Abs(src)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(r.file)
+	if err == nil {
+		if err := json.Unmarshal(data, &r.state); err != nil {
+			return fmt.Errorf("Corrupted reshard checkpoint %s: %v", r.file, err)
+		}
+		if r.state.Source != source || r.state.Shards != newCount || uint(len(r.state.Done)) != srcCount {
+			return fmt.Errorf("Destination %s is resharded from %s into %v shards", dst, r.state.Source, r.state.Shards)
+		}
+		for _, done := range r.state.Done {
+			if done {
+				r.done++
+			}
+		}
+		return nil
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if _, err := os.Stat(dst); err == nil {
+		return fmt.Errorf("Destination %s already exists", dst)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	r.state = reshardState{
+		Source: source,
+		Shards: newCount,
+		Done:   make([]bool, srcCount),
+		Last:   make([][]byte, srcCount),
+	}
+	// the destination is created with the checkpoint in place, so a crash
+	// never leaves it without one
+	tmp := dst + ".tmp"
+	if err := os.RemoveAll(tmp); err != nil {
+		return err
+	}
+	if err := os.Mkdir(tmp, 0700); err != nil {
+		return err
+	}
+	if err := writeJSON(filepath.Join(tmp, reshardStateFile), &r.state); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+// copyShard copies the i-th source shard starting after its last checkpoint.
+func (r *resharder) copyShard(i uint, name string) error {
+	db, err := rdb.OpenDbForReadOnly(r.opts, name, false)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	ro := rdb.NewDefaultReadOptions()
+	defer ro.Destroy()
+	ro.SetFillCache(false)
+	it := db.NewIterator(ro)
+	defer it.Close()
+
+	batches := make([]*rdb.WriteBatch, len(r.out.dbs))
+	for j := range batches {
+		batches[j] = rdb.NewWriteBatch()
+		defer batches[j].Destroy()
+	}
+
+	r.mu.Lock()
+	last := append([]byte(nil), r.state.Last[i]...)
+	r.mu.Unlock()
+	if last != nil {
+		it.Seek(last)
+		if it.Valid() && bytes.Equal(it.Key(), last) {
+			it.Next()
+		}
+	} else {
+		it.SeekToFirst()
+	}
+
+	n := 0
+	for ; it.Valid(); it.Next() {
+		key := it.Key()
+		batches[r.out.Index(key)].Put(key, it.Value())
+		if n++; n == r.batchSize {
+			last = append(last[:0], key...)
+			if err := r.checkpoint(i, batches, last, n, false); err != nil {
+				return err
+			}
+			n = 0
+		}
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	return r.checkpoint(i, batches, last, n, true)
+}
+
+// checkpoint writes the pending batches and records the last copied key of
+// the i-th source shard.
+func (r *resharder) checkpoint(i uint, batches []*rdb.WriteBatch, last []byte, n int, done bool) error {
+	for j, wb := range batches {
+		if wb.Count() == 0 {
+			continue
+		}
+		if err := r.out.dbs[j].Write(r.wo, wb); err != nil {
+			return err
+		}
+		wb.Clear()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.state.Last[i] = append([]byte(nil), last...)
+	r.state.Done[i] = done
+	if err := writeJSON(r.file, &r.state); err != nil {
+		return err
+	}
+	r.keys += uint64(n)
+	if done {
+		r.done++
+	}
+	if r.progress != nil {
+		r.progress(ReshardProgress{Keys: r.keys, Done: r.done, Shards: uint(len(r.state.Done))})
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package shard
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ingn/rdb"
+)
+
+func TestReshard(t *testing.T) {
+	sh, dir := newTestShard(t, 3)
+	defer os.RemoveAll(dir)
+
+	wo := rdb.NewDefaultWriteOptions()
+	for i := 0; i < 1000; i++ {
+		key := []byte(fmt.Sprintf("key%04d", i))
+		if err := sh.Put(wo, key, key); err != nil {
+			t.Fatalf("Should not get error: %v", err)
+		}
+	}
+	sh.Close()
+
+	dst := filepath.Join(tmpLocation(), "resharded")
+	defer os.RemoveAll(filepath.Dir(dst))
+	var progress ReshardProgress
+	err := Reshard(dir, dst, 5, &ReshardOptions{
+		BatchSize: 100,
+		Progress:  func(p ReshardProgress) { progress = p },
+	})
+	if err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	if progress.Keys != 1000 || progress.Done != 3 || progress.Shards != 3 {
+		t.Errorf("Wrong progress reported: %+v", progress)
+	}
+	if _, err := os.Stat(filepath.Join(dst, reshardStateFile)); !os.IsNotExist(err) {
+		t.Errorf("Checkpoint should be removed, got %v", err)
+	}
+	if err := Reshard(dir, dst, 5, nil); err == nil {
+		t.Errorf("Expecting error for existing destination")
+	}
+
+	// resume skips the source shards already done
+	resumed := filepath.Join(filepath.Dir(dst), "resumed")
+	if err := os.Mkdir(resumed, 0700); err != nil {
+		t.Fatalf(err.Error())
+	}
+	abs, _ := filepath.Abs(dir)
+	err = writeJSON(filepath.Join(resumed, reshardStateFile), &reshardState{
+		Source: abs,
+		Shards: 5,
+		Done:   []bool{true, false, false},
+		Last:   make([][]byte, 3),
+	})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	if err := Reshard(dir, resumed, 5, nil); err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	sh, err = OpenForReadOnly(rdb.NewDefaultOptions(), resumed, 5, false)
+	if err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	ro := sh.NewReadOptions()
+	for i := 0; i < 1000; i++ {
+		key := []byte(fmt.Sprintf("key%04d", i))
+		val, _ := sh.GetBytes(ro, key)
+		if skipped := DefaultPartitioner.Partition(key, 3) == 0; skipped != (val == nil) {
+			t.Fatalf("Wrong resume of %s: %q", key, val)
+		}
+	}
+	ro.Destroy()
+	sh.Close()
+
+	out, err := OpenForReadOnly(rdb.NewDefaultOptions(), dst, 5, false)
+	if err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	defer out.Close()
+	ro = out.NewReadOptions()
+	defer ro.Destroy()
+	for i := 0; i < 1000; i++ {
+		key := []byte(fmt.Sprintf("key%04d", i))
+		if val, err := out.GetBytes(ro, key); err != nil || !bytes.Equal(val, key) {
+			t.Fatalf("Wrong value of %s: %q (%v)", key, val, err)
+		}
+	}
+}
+
+func TestReshardInterruptedStart(t *testing.T) {
+	sh, dir := newTestShard(t, 2)
+	defer os.RemoveAll(dir)
+	sh.Close()
+
+	dst := filepath.Join(tmpLocation(), "resharded")
+	defer os.RemoveAll(filepath.Dir(dst))
+	// a crash while creating the destination leaves only the temporary one
+	if err := os.MkdirAll(dst+".tmp", 0700); err != nil {
+		t.Fatalf(err.Error())
+	}
+	opts := rdb.NewDefaultOptions()
+	defer opts.Destroy()
+	if err := Reshard(dir, dst, 3, &ReshardOptions{Options: opts}); err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	if _, err := os.Stat(dst + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("Temporary destination should be removed, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package shard
+
+import "github.com/ingn/rdb"
+
+// Partitioner returns the partitioner used to route keys.
+func (s *Shard) Partitioner() Partitioner {
+	return s.partitioner
+}
+
+// Index returns the index of the shard owning the key.
+func (s *Shard) Index(key []byte) uint {
+	return s.partitioner.Partition(key, uint(len(s.dbs)))
+}
+
+// DBFor returns the database of the shard owning the key or
+// ErrShardUnavailable. The database must not be used after the shard is
+// detached.
+func (s *Shard) DBFor(key []byte) (*rdb.DB, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.db(s.Index(key))
+}
+
+// Get returns the data associated with the key from the owning shard.
+func (s *Shard) Get(opts *ReadOptions, key []byte) (*rdb.Slice, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i := s.Index(key)
+	db, err := s.readDB(i, opts)
+	if err != nil {
+		return nil, err
+	}
+	return db.Get(opts.opts[i], key)
+}
+
+// GetBytes is like Get but returns a copy of the data.
+func (s *Shard) GetBytes(opts *ReadOptions, key []byte) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i := s.Index(key)
+	db, err := s.readDB(i, opts)
+	if err != nil {
+		return nil, err
+	}
+	return db.GetBytes(opts.opts[i], key)
+}
+
+// Put writes data associated with a key to the owning shard.
+func (s *Shard) Put(opts *rdb.WriteOptions, key, value []byte) error {
+	s.writes.RLock()
+	defer s.writes.RUnlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	db, err := s.db(s.Index(key))
+	if err != nil {
+		return err
+	}
+	return db.Put(opts, key, value)
+}
+
+// Delete removes the data associated with the key from the owning shard.
+func (s *Shard) Delete(opts *rdb.WriteOptions, key []byte) error {
+	s.writes.RLock()
+	defer s.writes.RUnlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	db, err := s.db(s.Index(key))
+	if err != nil {
+		return err
+	}
+	return db.Delete(opts, key)
+}
+
+// Merge merges the data associated with the key with the actual data in the
+// owning shard.
+func (s *Shard) Merge(opts *rdb.WriteOptions, key, value []byte) error {
+	s.writes.RLock()
+	defer s.writes.RUnlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	db, err := s.db(s.Index(key))
+	if err != nil {
+		return err
+	}
+	return db.Merge(opts, key, value)
+}
+
+// WriteFor writes the batch to the shard owning the key. All records of the
+// batch have to belong to that shard, it's up to the caller to ensure it
+// (e.g. with a partitioner placing keys by their prefix).
+func (s *Shard) WriteFor(opts *rdb.WriteOptions, key []byte, batch *rdb.WriteBatch) error {
+	s.writes.RLock()
+	defer s.writes.RUnlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	db, err := s.db(s.Index(key))
+	if err != nil {
+		return err
+	}
+	return db.Write(opts, batch)
+}
+
+// NewIteratorFor returns an Iterator over the shard owning the key.
+func (s *Shard) NewIteratorFor(opts *ReadOptions, key []byte) (*rdb.Iterator, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i := s.Index(key)
+	db, err := s.readDB(i, opts)
+	if err != nil {
+		return nil, err
+	}
+	return db.NewIterator(opts.opts[i]), nil
+}
@@ -1,162 +1,223 @@
 package shard
 
 import (
-	"fmt"
 	"io/ioutil"
-	"os"
 	"path/filepath"
-	"regexp"
 	"sync"
 
 	"github.com/ingn/rdb"
 )
 
-var ShardNameFn = func(i uint) string { return fmt.Sprintf("%03d", i) }
-
 type Shard struct {
-	dbs []*rdb.DB
+	name        string
+	manifest    *Manifest
+	dbs         []*rdb.DB
+	partitioner Partitioner
+
+	// openFn opens the database of a shard, it's kept to reattach shards.
+	openFn func(path string) (*rdb.DB, error)
+	// failures holds the reason why a shard is unavailable.
+	failures []error
+	// res are the resources shared by the shards, nil unless opened with
+	// Options.
+	res *resources
+
+	// mu guards dbs and failures while shards are reattached, it also
+	// pauses writes while a snapshot is taken.
+	mu sync.RWMutex
+	// writes is held for reading by writes and for writing while a backup
+	// is taken, so only writes are paused. It's taken before mu.
+	writes sync.RWMutex
 }
 
 func Open(opts *rdb.Options, name string, shardsNum uint) (*Shard, error) {
-	if err := checkValid(name, shardsNum); err != nil {
+	return OpenWithPartitioner(opts, name, shardsNum, DefaultPartitioner)
+}
+
+// OpenWithPartitioner opens the shards and routes keys with the given partitioner.
+// The partitioner has to match the one recorded in the manifest.
+func OpenWithPartitioner(opts *rdb.Options, name string, shardsNum uint, p Partitioner) (*Shard, error) {
+	m, stored, err := prepare(name, shardsNum, p, false)
+	if err != nil {
 		return nil, err
 	}
-	s := &Shard{}
-	for i := uint(0); i < shardsNum; i++ {
-		sName := filepath.Join(name, ShardNameFn(i))
-		db, err := rdb.OpenDb(opts, sName)
-		if err != nil {
+	s := &Shard{name: name, manifest: m, partitioner: p}
+	s.openFn = func(path string) (*rdb.DB, error) {
+		return rdb.OpenDb(opts, path)
+	}
+	if err := s.open(false); err != nil {
+		return nil, err
+	}
+	if !stored {
+		if err := WriteManifest(name, m); err != nil {
 			s.Close()
 			return nil, err
 		}
-		s.dbs = append(s.dbs, db)
 	}
 	return s, nil
 }
 
 func OpenForReadOnly(opts *rdb.Options, name string, shardsNum uint, errorIfLogFileExist bool) (*Shard, error) {
-	if err := checkValid(name, shardsNum); err != nil {
+	return OpenForReadOnlyWithPartitioner(opts, name, shardsNum, errorIfLogFileExist, DefaultPartitioner)
+}
+
+// OpenForReadOnlyWithPartitioner opens the shards for readonly usage and
+// routes keys with the given partitioner.
+// The partitioner has to match the one recorded in the manifest.
+func OpenForReadOnlyWithPartitioner(opts *rdb.Options, name string, shardsNum uint, errorIfLogFileExist bool, p Partitioner) (*Shard, error) {
+	m, _, err := prepare(name, shardsNum, p, true)
+	if err != nil {
 		return nil, err
 	}
-	s := &Shard{}
-	for i := uint(0); i < shardsNum; i++ {
-		sName := filepath.Join(name, ShardNameFn(i))
-		db, err := rdb.OpenDbForReadOnly(opts, sName, errorIfLogFileExist)
-		if err != nil {
-			s.Close()
-			return nil, err
-		}
-		s.dbs = append(s.dbs, db)
+	s := &Shard{name: name, manifest: m, partitioner: p}
+	s.openFn = func(path string) (*rdb.DB, error) {
+		return rdb.OpenDbForReadOnly(opts, path, errorIfLogFileExist)
+	}
+	if err := s.open(false); err != nil {
+		return nil, err
 	}
 	return s, nil
 }
 
-type errors []error
+// openLayoutForReadOnly opens the shards for readonly usage with the layout
+// recorded in the manifest, without checking the partitioner. Keys can't be
+// routed, so the Shard is only used internally by operations spanning all
+// the shards.
+func openLayoutForReadOnly(opts *rdb.Options, name string) (*Shard, error) {
+	m, err := readLayout(name)
+	if err != nil {
+		return nil, err
+	}
+	s := &Shard{name: name, manifest: m}
+	s.openFn = func(path string) (*rdb.DB, error) {
+		return rdb.OpenDbForReadOnly(opts, path, false)
+	}
+	if err := s.open(false); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
 
-func (e errors) Error() string {
-	res := ""
-	for _, err := range e {
-		res += err.Error()
+// open opens the databases of all the shards in parallel. If any of them
+// fails, the opened ones are closed again, unless degraded is set and at
+// least one shard is open.
+func (s *Shard) open(degraded bool) error {
+	s.dbs = make([]*rdb.DB, s.manifest.Shards)
+	s.failures = make([]error, s.manifest.Shards)
+	err := s.parallel(func(i uint) error {
+		db, err := s.openFn(s.path(i))
+		s.dbs[i], s.failures[i] = db, err
+		return err
+	})
+	if err != nil && (!degraded || len(err.(*MultiError).Errors) == len(s.dbs)) {
+		s.Close()
+		return err
 	}
-	return res
+	return nil
 }
 
+// Name returns the directory of the shard set.
+func (s *Shard) Name() string {
+	return s.name
+}
+
+// Manifest returns a copy of the manifest the shards were opened with.
+func (s *Shard) Manifest() Manifest {
+	return *s.manifest
+}
+
+// Flush triggers a manual flush of all the shards.
 func (s *Shard) Flush(opts *rdb.FlushOptions) error {
-	wg := sync.WaitGroup{}
-	wg.Add(len(s.dbs))
-	err := errors(nil)
-	l := sync.RWMutex{}
-	for _, db := range s.dbs {
-		go func(db *rdb.DB) {
-			if e := db.Flush(opts); e != nil {
-				l.Lock()
-				err = append(err, e)
-				l.Unlock()
-			}
-			wg.Done()
-		}(db)
-	}
-	wg.Wait()
-	if len(err) == 0 {
-		return nil
-	}
-	return err
+	return s.each(func(i uint, db *rdb.DB) error {
+		return db.Flush(opts)
+	})
 }
 
-func (s *Shard) CompactRange(r rdb.Range) {
-	wg := sync.WaitGroup{}
-	wg.Add(len(s.dbs))
-	for _, db := range s.dbs {
-		go func(db *rdb.DB) {
-			db.CompactRange(r)
-			wg.Done()
-		}(db)
-	}
-	wg.Wait()
+// CompactRange runs a manual compaction on the Range of keys given on all
+// the shards.
+func (s *Shard) CompactRange(r rdb.Range) error {
+	opts := rdb.NewDefaultCompactRangeOptions()
+	defer opts.Destroy()
+	return s.CompactRangeOpt(opts, r)
+}
+
+// CompactRangeOpt runs a manual compaction on the Range of keys given on all
+// the shards with the compaction options.
+func (s *Shard) CompactRangeOpt(opts *rdb.CompactRangeOptions, r rdb.Range) error {
+	return s.each(func(i uint, db *rdb.DB) error {
+		return db.CompactRangeOpt(opts, r)
+	})
 }
 
+// DBs returns the databases of the shards, unavailable shards are nil.
 func (s *Shard) DBs() []*rdb.DB {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	return append([]*rdb.DB(nil), s.dbs...)
 }
 
-func (s *Shard) Close() {
-	wg := sync.WaitGroup{}
-	wg.Add(len(s.dbs))
-	for _, db := range s.dbs {
-		go func(db *rdb.DB) {
-			db.Close()
-			wg.Done()
-		}(db)
+// Close closes the databases of all the shards and releases the resources
+// shared by them.
+func (s *Shard) Close() error {
+	err := s.each(func(i uint, db *rdb.DB) error {
+		return db.CloseWithError()
+	})
+	if s.res != nil {
+		s.res.destroy()
+		s.res = nil
 	}
-	wg.Wait()
+	return err
 }
 
-func GetShardNum(name string) uint {
-	if files, err := ioutil.ReadDir(name); os.IsNotExist(err) {
-		return 0
-	} else {
-		shards := map[string]bool{}
-		for _, file := range files {
-			shards[file.Name()] = true
-		}
-		i := 0
-		for shards[ShardNameFn(uint(i))] {
-			i++
-		}
-		if len(shards) != i {
-			return 0
+// path returns the directory of the i-th shard.
+func (s *Shard) path(i uint) string {
+	return filepath.Join(s.name, s.manifest.ShardName(i))
+}
+
+// each runs fn on the database of every available shard in parallel and
+// collects the failures into a MultiError. mu is held until all of them
+// return, so no shard is detached while in use.
+func (s *Shard) each(fn func(i uint, db *rdb.DB) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parallel(func(i uint) error {
+		if s.dbs[i] == nil {
+			return nil
 		}
-		return uint(i)
+		return fn(i, s.dbs[i])
+	})
+}
+
+// parallel runs fn for every shard index in parallel and collects the
+// failures into a MultiError.
+func (s *Shard) parallel(fn func(i uint) error) error {
+	errs := &MultiError{}
+	wg := sync.WaitGroup{}
+	l := sync.Mutex{}
+	for i := uint(0); i < uint(len(s.dbs)); i++ {
+		wg.Add(1)
+		go func(i uint) {
+			defer wg.Done()
+			if err := fn(i); err != nil {
+				l.Lock()
+				errs.add(i, s.path(i), err)
+				l.Unlock()
+			}
+		}(i)
 	}
+	wg.Wait()
+	return errs.errorOrNil()
 }
 
-func checkValid(name string, shardsNum uint) error {
-	if shardsNum == 0 || shardsNum > 999 {
-		return fmt.Errorf("Number of shards has to be bigger than 0 and lower than 1000")
+// GetShardNum returns the number of shards stored in the name directory or 0
+// if there are none.
+func GetShardNum(name string) uint {
+	if m, err := ReadManifest(name); err == nil {
+		return m.Shards
 	}
 	files, err := ioutil.ReadDir(name)
-	if os.IsNotExist(err) { // does not exists, let's create empty
-		return os.Mkdir(name, 0700)
-	} else if err != nil { // some other error related to ReadDir
-		return err
-	} else { // exists, let's check the content
-		shards := map[string]bool{}
-		for _, file := range files {
-			if matched, _ := regexp.MatchString(`\d{3}`, file.Name()); matched {
-				shards[file.Name()] = true
-			}
-		}
-		if len(shards) != 0 {
-			if uint(len(shards)) != shardsNum {
-				return fmt.Errorf("Wrong number of shards provided (found %v)", len(shards))
-			}
-			for i := uint(0); i < shardsNum; i++ {
-				sName := ShardNameFn(i)
-				if !shards[sName] {
-					return fmt.Errorf("Wrong number of shards provided (found %v)", len(shards))
-				}
-			}
-		}
+	if err != nil {
+		return 0
 	}
-	return nil
+	return legacyShardNum(files)
 }
@@ -1,6 +1,8 @@
 package shard
 
 import (
+	"bytes"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
@@ -61,6 +63,98 @@ func TestCreateAndOpenWithWrongShardsNum(t *testing.T) {
 	}
 }
 
+func TestRouting(t *testing.T) {
+	dir := tmpLocation()
+	defer os.RemoveAll(dir)
+
+	dbOpts := rdb.NewDefaultOptions()
+	dbOpts.SetCreateIfMissing(true)
+
+	sh, err := Open(dbOpts, dir, 5)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer sh.Close()
+
+	wo := rdb.NewDefaultWriteOptions()
+	ro := sh.NewReadOptions()
+	defer ro.Destroy()
+	for i := 0; i < 100; i++ {
+		key := []byte(fmt.Sprintf("key%03d", i))
+		if err := sh.Put(wo, key, key); err != nil {
+			t.Fatalf("Should not get error: %v", err)
+		}
+	}
+	for i, db := range sh.DBs() {
+		it := db.NewIterator(ro.Shard(uint(i)))
+		for it.SeekToFirst(); it.Valid(); it.Next() {
+			if idx := sh.Index(it.Key()); idx != uint(i) {
+				t.Errorf("Key %s stored in shard %v, expected %v", it.Key(), i, idx)
+			}
+		}
+		it.Close()
+	}
+	key := []byte("key042")
+	if val, err := sh.GetBytes(ro, key); err != nil || !bytes.Equal(val, key) {
+		t.Errorf("Wrong value %q (%v)", val, err)
+	}
+	if err := sh.Delete(wo, key); err != nil {
+		t.Errorf("Should not get error: %v", err)
+	}
+	if val, err := sh.GetBytes(ro, key); err != nil || val != nil {
+		t.Errorf("Expecting deleted key, got %q (%v)", val, err)
+	}
+}
+
+func TestHashPartitionerStable(t *testing.T) {
+	p := HashPartitioner{}
+	// placements of fnv1a64 v1, they must never change
+	pinned := []struct {
+		key     string
+		shard3  uint
+		shard16 uint
+	}{
+		{"", 2, 5},
+		{"a", 1, 12},
+		{"key", 2, 12},
+		{"key0001", 1, 15},
+		{"user:42", 2, 2},
+		{"hello world", 1, 7},
+	}
+	for _, c := range pinned {
+		if idx := p.Partition([]byte(c.key), 3); idx != c.shard3 {
+			t.Errorf("Key %q placed in shard %v of 3, expected %v", c.key, idx, c.shard3)
+		}
+		if idx := p.Partition([]byte(c.key), 16); idx != c.shard16 {
+			t.Errorf("Key %q placed in shard %v of 16, expected %v", c.key, idx, c.shard16)
+		}
+	}
+	for _, n := range []uint{1, 7, 64} {
+		for i := 0; i < 100; i++ {
+			key := []byte(fmt.Sprintf("key%03d", i))
+			idx := p.Partition(key, n)
+			if idx >= n {
+				t.Fatalf("Partition out of range: %v >= %v", idx, n)
+			}
+			if again := p.Partition(key, n); again != idx {
+				t.Fatalf("Unstable partition for %s: %v != %v", key, idx, again)
+			}
+		}
+	}
+}
+
+func newTestShard(t *testing.T, shardsNum uint) (*Shard, string) {
+	dir := tmpLocation()
+	dbOpts := rdb.NewDefaultOptions()
+	dbOpts.SetCreateIfMissing(true)
+	sh, err := Open(dbOpts, dir, shardsNum)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf(err.Error())
+	}
+	return sh, dir
+}
+
 func tmpLocation() string {
 	name, err := ioutil.TempDir("", "shard")
 	if err != nil {
@@ -0,0 +1,35 @@
+package shard
+
+import "github.com/ingn/rdb"
+
+// Snapshot provides a consistent view of read operations across all the
+// shards. Use it with ReadOptions.SetSnapshot.
+type Snapshot struct {
+	snaps []*rdb.Snapshot
+}
+
+// NewSnapshot creates a new snapshot of all the shards. Writes made through
+// the Shard are paused while the snapshot of every shard is taken, so none
+// of them is seen partially applied. Writes made directly to the databases
+// returned by DBs are not paused. Unavailable shards have no snapshot.
+func (s *Shard) NewSnapshot() *Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap := &Snapshot{snaps: make([]*rdb.Snapshot, len(s.dbs))}
+	for i, db := range s.dbs {
+		if db != nil {
+			snap.snaps[i] = db.NewSnapshot()
+		}
+	}
+	return snap
+}
+
+// Release removes the snapshot from the shards.
+func (snap *Snapshot) Release() {
+	for _, sn := range snap.snaps {
+		if sn != nil {
+			sn.Release()
+		}
+	}
+	snap.snaps = nil
+}
@@ -0,0 +1,93 @@
+package shard
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/ingn/rdb"
+)
+
+func TestSnapshot(t *testing.T) {
+	sh, dir := newTestShard(t, 4)
+	defer os.RemoveAll(dir)
+	defer sh.Close()
+
+	wo := rdb.NewDefaultWriteOptions()
+	for i := 0; i < 20; i++ {
+		key := []byte(fmt.Sprintf("key%02d", i))
+		if err := sh.Put(wo, key, []byte("old")); err != nil {
+			t.Fatalf("Should not get error: %v", err)
+		}
+	}
+
+	snap := sh.NewSnapshot()
+	defer snap.Release()
+	for i := 0; i < 20; i++ {
+		key := []byte(fmt.Sprintf("key%02d", i))
+		if err := sh.Put(wo, key, []byte("new")); err != nil {
+			t.Fatalf("Should not get error: %v", err)
+		}
+	}
+	if err := sh.Put(wo, []byte("key99"), []byte("new")); err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+
+	ro := sh.NewReadOptions()
+	defer ro.Destroy()
+	if err := ro.SetSnapshot(snap); err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	if val, err := sh.GetBytes(ro, []byte("key07")); err != nil || !bytes.Equal(val, []byte("old")) {
+		t.Errorf("Wrong value read from snapshot: %q (%v)", val, err)
+	}
+
+	it := sh.NewIterator(ro)
+	defer it.Close()
+	n := 0
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		if !bytes.Equal(it.Value(), []byte("old")) {
+			t.Errorf("Wrong value of %s read from snapshot: %q", it.Key(), it.Value())
+		}
+		n++
+	}
+	if n != 20 {
+		t.Errorf("Wrong number of keys in snapshot, expected 20 got %v", n)
+	}
+}
+
+func TestSnapshotUnavailableShard(t *testing.T) {
+	sh, dir := newTestShard(t, 2)
+	defer os.RemoveAll(dir)
+	defer sh.Close()
+
+	key := []byte("key")
+	if err := sh.Put(rdb.NewDefaultWriteOptions(), key, key); err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	i := sh.Index(key)
+	if err := sh.Detach(i); err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	snap := sh.NewSnapshot()
+	if err := sh.Reattach(i); err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+
+	ro := sh.NewReadOptions()
+	defer ro.Destroy()
+	if err := ro.SetSnapshot(snap); err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	// the shard reattached after the snapshot isn't read outside of it
+	if _, err := sh.GetBytes(ro, key); !errors.Is(err, ErrShardUnavailable) {
+		t.Errorf("Expecting unavailable shard, got %v", err)
+	}
+
+	snap.Release()
+	if err := ro.SetSnapshot(snap); err == nil {
+		t.Errorf("Expecting error for released snapshot")
+	}
+}
@@ -0,0 +1,121 @@
+package shard
+
+import (
+	"strconv"
+
+	"github.com/ingn/rdb"
+)
+
+// StatsProperties are the numeric properties collected by Stats.
+var StatsProperties = []string{
+	"rocksdb.estimate-num-keys",
+	"rocksdb.estimate-live-data-size",
+	"rocksdb.total-sst-files-size",
+	"rocksdb.live-sst-files-size",
+	"rocksdb.cur-size-all-mem-tables",
+	"rocksdb.size-all-mem-tables",
+	"rocksdb.estimate-table-readers-mem",
+	"rocksdb.estimate-pending-compaction-bytes",
+	"rocksdb.num-running-compactions",
+	"rocksdb.num-running-flushes",
+}
+
+// Property is the value of a database property on every shard.
+type Property struct {
+	Name string
+	// Shards holds the value of every shard, unavailable shards have an
+	// empty value.
+	Shards []string
+	// Numeric tells whether the values of all the available shards are
+	// numbers, only then Total is set.
+	Numeric bool
+	// Total is the sum of the values of all the shards.
+	Total uint64
+}
+
+// GetProperty returns the value of a database property of every shard.
+// Numeric values are summed up.
+func (s *Shard) GetProperty(name string) *Property {
+	p := &Property{Name: name, Shards: make([]string, len(s.dbs)), Numeric: true}
+	available := make([]bool, len(s.dbs))
+	s.each(func(i uint, db *rdb.DB) error {
+		p.Shards[i], available[i] = db.GetProperty(name), true
+		return nil
+	})
+	for i, v := range p.Shards {
+		if !available[i] {
+			continue
+		}
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			p.Numeric, p.Total = false, 0
+			break
+		}
+		p.Total += n
+	}
+	return p
+}
+
+// Stats holds the StatsProperties of every shard and their sum.
+type Stats struct {
+	// Total sums the properties of all the shards.
+	Total map[string]uint64
+	// Shards holds the properties of every shard, nil for unavailable
+	// shards.
+	Shards []map[string]uint64
+}
+
+// ReadStats collects the StatsProperties of the shard set stored in the name
+// directory, whatever partitioner it was created with. The shards are opened
+// read only with the layout of the returned manifest.
+func ReadStats(opts *rdb.Options, name string) (*Stats, *Manifest, error) {
+	s, err := openLayoutForReadOnly(opts, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer s.Close()
+	m := s.Manifest()
+	return s.Stats(), &m, nil
+}
+
+// Stats collects the StatsProperties from every shard. Properties a shard
+// does not report are left out of its breakdown.
+func (s *Shard) Stats() *Stats {
+	st := &Stats{Total: map[string]uint64{}, Shards: make([]map[string]uint64, len(s.dbs))}
+	s.each(func(i uint, db *rdb.DB) error {
+		props := map[string]uint64{}
+		for _, name := range StatsProperties {
+			if n, err := strconv.ParseUint(db.GetProperty(name), 10, 64); err == nil {
+				props[name] = n
+			}
+		}
+		st.Shards[i] = props
+		return nil
+	})
+	for _, props := range st.Shards {
+		for name, n := range props {
+			st.Total[name] += n
+		}
+	}
+	return st
+}
+
+// Skew returns the ratio of the biggest value of the property to the average
+// of the available shards, 1 means the property is evenly spread. It's 0 if
+// no shard reports the property.
+func (st *Stats) Skew(name string) float64 {
+	max, shards := uint64(0), 0
+	for _, props := range st.Shards {
+		if props == nil {
+			continue
+		}
+		shards++
+		if n := props[name]; n > max {
+			max = n
+		}
+	}
+	if shards == 0 || st.Total[name] == 0 {
+		return 0
+	}
+	return float64(max) * float64(shards) / float64(st.Total[name])
+}
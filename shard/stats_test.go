@@ -0,0 +1,72 @@
+package shard
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/ingn/rdb"
+)
+
+func TestStats(t *testing.T) {
+	sh, dir := newTestShard(t, 3)
+	defer os.RemoveAll(dir)
+	defer sh.Close()
+
+	wo := rdb.NewDefaultWriteOptions()
+	for i := 0; i < 300; i++ {
+		key := []byte(fmt.Sprintf("key%03d", i))
+		if err := sh.Put(wo, key, key); err != nil {
+			t.Fatalf("Should not get error: %v", err)
+		}
+	}
+	if err := sh.Flush(rdb.NewDefaultFlushOptions()); err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+
+	p := sh.GetProperty("rocksdb.estimate-num-keys")
+	if !p.Numeric || p.Total != 300 || len(p.Shards) != 3 {
+		t.Errorf("Wrong property %+v", p)
+	}
+	if p := sh.GetProperty("rocksdb.stats"); p.Numeric || p.Shards[0] == "" {
+		t.Errorf("Stats should not be numeric: %+v", p)
+	}
+
+	st := sh.Stats()
+	sum := uint64(0)
+	for _, props := range st.Shards {
+		sum += props["rocksdb.estimate-num-keys"]
+	}
+	if st.Total["rocksdb.estimate-num-keys"] != 300 || sum != 300 {
+		t.Errorf("Wrong number of keys %v, shards sum to %v", st.Total["rocksdb.estimate-num-keys"], sum)
+	}
+	if st.Total["rocksdb.total-sst-files-size"] == 0 {
+		t.Errorf("Expecting sst files after flush")
+	}
+	if skew := st.Skew("rocksdb.estimate-num-keys"); skew < 1 || skew > 3 {
+		t.Errorf("Wrong skew %v", skew)
+	}
+}
+
+func TestStatsCustomPartitioner(t *testing.T) {
+	dir := tmpLocation()
+	defer os.RemoveAll(dir)
+	dbOpts := rdb.NewDefaultOptions()
+	dbOpts.SetCreateIfMissing(true)
+	sh, err := OpenWithPartitioner(dbOpts, dir, 2, testPartitioner{})
+	if err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	if err := sh.Put(rdb.NewDefaultWriteOptions(), []byte("key"), []byte("value")); err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	sh.Close()
+
+	st, m, err := ReadStats(rdb.NewDefaultOptions(), dir)
+	if err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	if m.Partitioner != "test" || len(st.Shards) != 2 {
+		t.Errorf("Wrong stats %+v of %+v", st, m)
+	}
+}
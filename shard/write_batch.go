@@ -0,0 +1,135 @@
+package shard
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/ingn/rdb"
+)
+
+// WriteBatch is a batching of Puts, Merges and Deletes spanning the shards.
+// Records are bucketed into one rdb.WriteBatch per owning shard.
+type WriteBatch struct {
+	s       *Shard
+	batches []*rdb.WriteBatch
+}
+
+// NewWriteBatch creates a WriteBatch routing records like the shard does.
+func (s *Shard) NewWriteBatch() *WriteBatch {
+	return &WriteBatch{
+		s:       s,
+		batches: make([]*rdb.WriteBatch, len(s.dbs)),
+	}
+}
+
+// Put queues a key-value pair.
+func (wb *WriteBatch) Put(key, value []byte) {
+	wb.batch(key).Put(key, value)
+}
+
+// Merge queues a merge of "value" with the existing value of "key".
+func (wb *WriteBatch) Merge(key, value []byte) {
+	wb.batch(key).Merge(key, value)
+}
+
+// Delete queues a deletion of the data at key.
+func (wb *WriteBatch) Delete(key []byte) {
+	wb.batch(key).Delete(key)
+}
+
+// Shard returns the batch of the i-th shard, nil if no record was queued for
+// it.
+func (wb *WriteBatch) Shard(i uint) *rdb.WriteBatch {
+	return wb.batches[i]
+}
+
+// Count returns the number of updates in the batch.
+func (wb *WriteBatch) Count() int {
+	n := 0
+	for _, b := range wb.batches {
+		if b != nil {
+			n += b.Count()
+		}
+	}
+	return n
+}
+
+// Clear removes all the enqueued Put, Merges and Deletes.
+func (wb *WriteBatch) Clear() {
+	for _, b := range wb.batches {
+		if b != nil {
+			b.Clear()
+		}
+	}
+}
+
+// Destroy deallocates the batches of all the shards.
+func (wb *WriteBatch) Destroy() {
+	for i, b := range wb.batches {
+		if b != nil {
+			b.Destroy()
+			wb.batches[i] = nil
+		}
+	}
+}
+
+func (wb *WriteBatch) batch(key []byte) *rdb.WriteBatch {
+	i := wb.s.Index(key)
+	if wb.batches[i] == nil {
+		wb.batches[i] = rdb.NewWriteBatch()
+	}
+	return wb.batches[i]
+}
+
+// WriteResult reports which shards committed their part of a WriteBatch.
+// Shards without records in the batch are in neither list.
+type WriteResult struct {
+	Committed []uint
+	Failed    []*ShardError
+}
+
+// Err returns nil if every shard committed its part of the batch, or a
+// *MultiError with the failed shards otherwise.
+func (r *WriteResult) Err() error {
+	errs := &MultiError{Errors: r.Failed}
+	return errs.errorOrNil()
+}
+
+// Write applies the batch of every shard in parallel. Shards apply their part
+// atomically, but a failure of one shard doesn't revert the others, the
+// result tells which shards committed.
+func (s *Shard) Write(opts *rdb.WriteOptions, batch *WriteBatch) *WriteResult {
+	s.writes.RLock()
+	defer s.writes.RUnlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	res := &WriteResult{}
+	wg := sync.WaitGroup{}
+	l := sync.Mutex{}
+	for i, b := range batch.batches {
+		if b == nil || b.Count() == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(i uint, b *rdb.WriteBatch) {
+			defer wg.Done()
+			db, err := s.db(i)
+			if err == nil {
+				err = db.Write(opts, b)
+			}
+			l.Lock()
+			if shErr, ok := err.(*ShardError); ok {
+				res.Failed = append(res.Failed, shErr)
+			} else if err != nil {
+				res.Failed = append(res.Failed, &ShardError{Index: i, Path: s.path(i), Err: err})
+			} else {
+				res.Committed = append(res.Committed, i)
+			}
+			l.Unlock()
+		}(uint(i), b)
+	}
+	wg.Wait()
+	sort.Slice(res.Committed, func(i, j int) bool { return res.Committed[i] < res.Committed[j] })
+	return res
+}
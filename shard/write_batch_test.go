@@ -0,0 +1,59 @@
+package shard
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/ingn/rdb"
+)
+
+func TestWriteBatch(t *testing.T) {
+	sh, dir := newTestShard(t, 4)
+	defer os.RemoveAll(dir)
+	defer sh.Close()
+
+	wo := rdb.NewDefaultWriteOptions()
+	if err := sh.Put(wo, []byte("deleted"), []byte("foo")); err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+
+	wb := sh.NewWriteBatch()
+	defer wb.Destroy()
+	owners := map[uint]bool{sh.Index([]byte("deleted")): true}
+	for i := 0; i < 10; i++ {
+		key := []byte(fmt.Sprintf("key%d", i))
+		wb.Put(key, key)
+		owners[sh.Index(key)] = true
+	}
+	wb.Delete([]byte("deleted"))
+	if wb.Count() != 11 {
+		t.Errorf("Wrong count, expected 11 got %v", wb.Count())
+	}
+
+	res := sh.Write(wo, wb)
+	if err := res.Err(); err != nil {
+		t.Fatalf("Should not get error: %v", err)
+	}
+	if len(res.Committed) != len(owners) {
+		t.Errorf("Wrong committed shards %v, expected %v", res.Committed, owners)
+	}
+	for _, i := range res.Committed {
+		if !owners[i] {
+			t.Errorf("Shard %v should not be written", i)
+		}
+	}
+
+	ro := sh.NewReadOptions()
+	defer ro.Destroy()
+	for i := 0; i < 10; i++ {
+		key := []byte(fmt.Sprintf("key%d", i))
+		if val, err := sh.GetBytes(ro, key); err != nil || !bytes.Equal(val, key) {
+			t.Errorf("Wrong value of %s: %q (%v)", key, val, err)
+		}
+	}
+	if val, err := sh.GetBytes(ro, []byte("deleted")); err != nil || val != nil {
+		t.Errorf("Expecting deleted key, got %q (%v)", val, err)
+	}
+}
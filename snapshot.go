@@ -9,13 +9,17 @@ type Snapshot struct {
 	cDb *C.rocksdb_t
 }
 
-// NewNativeSnapshot creates a Snapshot object.
+// NewNativeSnapshot creates a Snapshot object. Snapshots without cDb are
+// owned by someone else, e.g. a transaction, and are not released by
+// Release.
 func NewNativeSnapshot(c *C.rocksdb_snapshot_t, cDb *C.rocksdb_t) *Snapshot {
 	return &Snapshot{c, cDb}
 }
 
 // Release removes the snapshot from the database's list of snapshots.
 func (s *Snapshot) Release() {
-	C.rocksdb_release_snapshot(s.cDb, s.c)
+	if s.cDb != nil {
+		C.rocksdb_release_snapshot(s.cDb, s.c)
+	}
 	s.c, s.cDb = nil, nil
 }
@@ -0,0 +1,109 @@
+package rdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+import "C"
+import "unsafe"
+
+// SstFileWriter creates SST files which can be ingested into a database with
+// DB.IngestExternalFile. Keys have to be added in increasing order of the
+// comparator of the options.
+type SstFileWriter struct {
+	c *C.rocksdb_sstfilewriter_t
+
+	// Hold references for GC.
+	opts *Options
+}
+
+// NewSstFileWriter creates a SstFileWriter object. The options have to use
+// the comparator of the database the files are ingested into.
+func NewSstFileWriter(envOpts *EnvOptions, opts *Options) *SstFileWriter {
+	return &SstFileWriter{
+		c:    C.rocksdb_sstfilewriter_create(envOpts.c, opts.c),
+		opts: opts,
+	}
+}
+
+// Open creates the file at path, the following records are written into it.
+func (w *SstFileWriter) Open(path string) error {
+	var cErr *C.char
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	C.rocksdb_sstfilewriter_open(w.c, cPath, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// Put adds a key-value pair to the file.
+func (w *SstFileWriter) Put(key, value []byte) error {
+	var (
+		cErr   *C.char
+		cKey   = byteToChar(key)
+		cValue = byteToChar(value)
+	)
+	C.rocksdb_sstfilewriter_put(w.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// Merge adds a merge of "value" with the existing value of "key" to the
+// file.
+func (w *SstFileWriter) Merge(key, value []byte) error {
+	var (
+		cErr   *C.char
+		cKey   = byteToChar(key)
+		cValue = byteToChar(value)
+	)
+	C.rocksdb_sstfilewriter_merge(w.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// Delete adds a deletion of the data at key to the file.
+func (w *SstFileWriter) Delete(key []byte) error {
+	var (
+		cErr *C.char
+		cKey = byteToChar(key)
+	)
+	C.rocksdb_sstfilewriter_delete(w.c, cKey, C.size_t(len(key)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// Finish finalizes the file, it can't be added to anymore.
+func (w *SstFileWriter) Finish() error {
+	var cErr *C.char
+	C.rocksdb_sstfilewriter_finish(w.c, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// FileSize returns the current size of the file.
+func (w *SstFileWriter) FileSize() uint64 {
+	var cSize C.uint64_t
+	C.rocksdb_sstfilewriter_file_size(w.c, &cSize)
+	return uint64(cSize)
+}
+
+// Destroy deallocates the SstFileWriter object.
+func (w *SstFileWriter) Destroy() {
+	C.rocksdb_sstfilewriter_destroy(w.c)
+	w.c = nil
+	w.opts = nil
+}
@@ -0,0 +1,53 @@
+package rdb
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestSstFileWriterIngest(t *testing.T) {
+	db := newTestDB(t, "TestSstFileWriterIngest", nil)
+	defer db.Close()
+
+	dir, err := ioutil.TempDir("", "gorocksdb-TestSstFileWriterIngest-files")
+	ensure.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	envOpts := NewDefaultEnvOptions()
+	defer envOpts.Destroy()
+	opts := NewDefaultOptions()
+	w := NewSstFileWriter(envOpts, opts)
+	defer w.Destroy()
+
+	file := filepath.Join(dir, "1.sst")
+	ensure.Nil(t, w.Open(file))
+	ensure.Nil(t, w.Put([]byte("key1"), []byte("val1")))
+	ensure.Nil(t, w.Put([]byte("key2"), []byte("val2")))
+	ensure.True(t, errors.Is(w.Put([]byte("key0"), []byte("val0")), ErrInvalidArgument))
+	ensure.Nil(t, w.Delete([]byte("key3")))
+	ensure.Nil(t, w.Finish())
+	ensure.True(t, w.FileSize() > 0)
+
+	wo := NewDefaultWriteOptions()
+	ensure.Nil(t, db.Put(wo, []byte("key3"), []byte("val3")))
+
+	ingestOpts := NewDefaultIngestExternalFileOptions()
+	defer ingestOpts.Destroy()
+	ensure.Nil(t, db.IngestExternalFile([]string{file}, ingestOpts))
+
+	ro := NewDefaultReadOptions()
+	v1, err := db.GetBytes(ro, []byte("key1"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v1, []byte("val1"))
+	v2, err := db.GetBytes(ro, []byte("key2"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v2, []byte("val2"))
+	v3, err := db.GetBytes(ro, []byte("key3"))
+	ensure.Nil(t, err)
+	ensure.True(t, v3 == nil)
+}
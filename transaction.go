@@ -0,0 +1,178 @@
+package rdb
+
+// #include "rocksdb/c.h"
+// #include "ext.h"
+import "C"
+import "unsafe"
+
+// Transaction is a set of reads and writes committed atomically, created by
+// TransactionDB.TransactionBegin. Waiting for a lock fails with an error
+// matching ErrLockTimeout or ErrDeadlock.
+type Transaction struct {
+	c *C.rocksdb_transaction_t
+
+	// snaps are the handles returned by GetSnapshot, freed in Destroy and
+	// when the transaction is reused.
+	snaps []*C.rocksdb_snapshot_t
+}
+
+// NewNativeTransaction creates a Transaction object.
+func NewNativeTransaction(c *C.rocksdb_transaction_t) *Transaction {
+	return &Transaction{c: c}
+}
+
+// Commit writes the changes of the transaction to the database and releases
+// its locks.
+func (txn *Transaction) Commit() error {
+	var cErr *C.char
+	C.rocksdb_transaction_commit(txn.c, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// Rollback discards the changes of the transaction and releases its locks.
+func (txn *Transaction) Rollback() error {
+	var cErr *C.char
+	C.rocksdb_transaction_rollback(txn.c, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// SetSavePoint records the state of the transaction, RollbackToSavePoint
+// returns to it.
+func (txn *Transaction) SetSavePoint() {
+	C.rocksdb_transaction_set_savepoint(txn.c)
+}
+
+// RollbackToSavePoint discards the changes made since the last SetSavePoint
+// and removes the save point. It fails with ErrNotFound if there is no save
+// point.
+func (txn *Transaction) RollbackToSavePoint() error {
+	var cErr *C.char
+	C.rocksdb_transaction_rollback_to_savepoint(txn.c, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// GetSnapshot returns the snapshot taken when the transaction began, nil if
+// it was not set by TransactionOptions.SetSetSnapshot. The transaction owns
+// the snapshot: it's valid until the transaction is destroyed or reused by
+// TransactionBegin, and its Release does nothing.
+func (txn *Transaction) GetSnapshot() *Snapshot {
+	cSnap := C.rocksdb_transaction_get_snapshot_ext(txn.c)
+	if cSnap == nil {
+		return nil
+	}
+	txn.snaps = append(txn.snaps, cSnap)
+	return NewNativeSnapshot(cSnap, nil)
+}
+
+// Get returns the data associated with the key, including the uncommitted
+// changes of the transaction.
+func (txn *Transaction) Get(opts *ReadOptions, key []byte) (*Slice, error) {
+	var (
+		cErr    *C.char
+		cValLen C.size_t
+		cKey    = byteToChar(key)
+	)
+	cValue := C.rocksdb_transaction_get(txn.c, opts.c, cKey, C.size_t(len(key)), &cValLen, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, newError(C.GoString(cErr))
+	}
+	return NewSlice(cValue, cValLen), nil
+}
+
+// GetForUpdate is like Get but also locks the key until the transaction
+// ends, exclusively unless it's only read.
+func (txn *Transaction) GetForUpdate(opts *ReadOptions, key []byte, exclusive bool) (*Slice, error) {
+	var (
+		cErr    *C.char
+		cValLen C.size_t
+		cKey    = byteToChar(key)
+	)
+	cValue := C.rocksdb_transaction_get_for_update(txn.c, opts.c, cKey, C.size_t(len(key)), &cValLen, boolToChar(exclusive), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, newError(C.GoString(cErr))
+	}
+	return NewSlice(cValue, cValLen), nil
+}
+
+// Put writes data associated with a key in the transaction and locks the key.
+func (txn *Transaction) Put(key, value []byte) error {
+	var (
+		cErr   *C.char
+		cKey   = byteToChar(key)
+		cValue = byteToChar(value)
+	)
+	C.rocksdb_transaction_put(txn.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// Delete removes the data associated with the key in the transaction and
+// locks the key.
+func (txn *Transaction) Delete(key []byte) error {
+	var (
+		cErr *C.char
+		cKey = byteToChar(key)
+	)
+	C.rocksdb_transaction_delete(txn.c, cKey, C.size_t(len(key)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// Merge merges the data associated with the key in the transaction and
+// locks the key.
+func (txn *Transaction) Merge(key, value []byte) error {
+	var (
+		cErr   *C.char
+		cKey   = byteToChar(key)
+		cValue = byteToChar(value)
+	)
+	C.rocksdb_transaction_merge(txn.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// NewIterator returns an Iterator over the database merged with the
+// uncommitted changes of the transaction.
+func (txn *Transaction) NewIterator(opts *ReadOptions) *Iterator {
+	cIter := C.rocksdb_transaction_create_iterator(txn.c, opts.c)
+	return NewNativeIterator(unsafe.Pointer(cIter))
+}
+
+// Destroy deallocates the Transaction object, an uncommitted transaction is
+// rolled back.
+func (txn *Transaction) Destroy() {
+	C.rocksdb_transaction_destroy(txn.c)
+	txn.freeSnapshots()
+	txn.c = nil
+}
+
+// freeSnapshots frees the handles returned by GetSnapshot.
+func (txn *Transaction) freeSnapshots() {
+	for _, cSnap := range txn.snaps {
+		C.rocksdb_transaction_snapshot_free_ext(cSnap)
+	}
+	txn.snaps = nil
+}
@@ -0,0 +1,55 @@
+package rdb
+
+// #include "rocksdb/c.h"
+import "C"
+import "unsafe"
+
+// TransactionLogIterator is used to iterate over the write batches stored
+// in the write-ahead logs, see DB.GetUpdatesSince.
+//
+// The iterator stops at the end of the logs written so far, a new one has
+// to be created to read the batches written later.
+type TransactionLogIterator struct {
+	c *C.rocksdb_wal_iterator_t
+}
+
+// NewNativeTransactionLogIterator creates a TransactionLogIterator object.
+func NewNativeTransactionLogIterator(c unsafe.Pointer) *TransactionLogIterator {
+	return &TransactionLogIterator{(*C.rocksdb_wal_iterator_t)(c)}
+}
+
+// Valid returns false once the iterator is past the last batch or failed.
+func (iter *TransactionLogIterator) Valid() bool {
+	return ucharToBool(C.rocksdb_wal_iter_valid(iter.c))
+}
+
+// Next moves the iterator to the next batch.
+func (iter *TransactionLogIterator) Next() {
+	C.rocksdb_wal_iter_next(iter.c)
+}
+
+// Err returns nil if no errors happened during iteration, or the actual
+// error otherwise.
+func (iter *TransactionLogIterator) Err() error {
+	var cErr *C.char
+	C.rocksdb_wal_iter_status(iter.c, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// GetBatch returns the current batch and the sequence number of its first
+// record. The batch has to be destroyed by the caller.
+func (iter *TransactionLogIterator) GetBatch() (*WriteBatch, uint64) {
+	var cSeq C.uint64_t
+	cBatch := C.rocksdb_wal_iter_get_batch(iter.c, &cSeq)
+	return NewNativeWriteBatch(cBatch), uint64(cSeq)
+}
+
+// Close closes the iterator.
+func (iter *TransactionLogIterator) Close() {
+	C.rocksdb_wal_iter_destroy(iter.c)
+	iter.c = nil
+}
@@ -0,0 +1,82 @@
+package rdb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestGetUpdatesSince(t *testing.T) {
+	db := newTestDB(t, "TestGetUpdatesSince", func(opts *Options) {
+		opts.SetWALTtlSeconds(3600)
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("val1")))
+	wb := NewWriteBatch()
+	wb.Put([]byte("key2"), []byte("val2"))
+	wb.Delete([]byte("key1"))
+	ensure.Nil(t, db.Write(wo, wb))
+	wb.Destroy()
+	ensure.DeepEqual(t, db.GetLatestSequenceNumber(), uint64(3))
+
+	iter, err := db.GetUpdatesSince(1)
+	ensure.Nil(t, err)
+	defer iter.Close()
+	var seqs []uint64
+	var counts []int
+	for ; iter.Valid(); iter.Next() {
+		batch, seq := iter.GetBatch()
+		seqs = append(seqs, seq)
+		counts = append(counts, batch.Count())
+		batch.Destroy()
+	}
+	ensure.DeepEqual(t, seqs, []uint64{1, 2})
+	ensure.DeepEqual(t, counts, []int{1, 2})
+}
+
+func TestSubscribe(t *testing.T) {
+	db := newTestDB(t, "TestSubscribe", func(opts *Options) {
+		opts.SetWALTtlSeconds(3600)
+	})
+	defer db.Close()
+	defer func(d time.Duration) { SubscribePollInterval = d }(SubscribePollInterval)
+	SubscribePollInterval = 10 * time.Millisecond
+
+	wo := NewDefaultWriteOptions()
+	ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("val1")))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := db.Subscribe(ctx, 1)
+	next := func() ChangeEvent {
+		select {
+		case ev := <-events:
+			ensure.Nil(t, ev.Err)
+			return ev
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for change event")
+		}
+		return ChangeEvent{}
+	}
+
+	ev := next()
+	ensure.DeepEqual(t, ev.Sequence, uint64(1))
+	ensure.DeepEqual(t, len(ev.Records), 1)
+	ensure.DeepEqual(t, ev.Records[0].Key, []byte("key1"))
+	ensure.DeepEqual(t, ev.Records[0].Value, []byte("val1"))
+
+	// flushing switches to a new log
+	ensure.Nil(t, db.Flush(NewDefaultFlushOptions()))
+	ensure.Nil(t, db.Delete(wo, []byte("key1")))
+	ev = next()
+	ensure.DeepEqual(t, ev.Sequence, uint64(2))
+	ensure.DeepEqual(t, ev.Records[0].Type, WriteBatchRecordTypeDeletion)
+
+	cancel()
+	for range events {
+	}
+}
@@ -0,0 +1,140 @@
+package rdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+import "C"
+import "unsafe"
+
+// TransactionDB is a database supporting pessimistic transactions, keys
+// written by a Transaction are locked until it commits or rolls back.
+type TransactionDB struct {
+	c         *C.rocksdb_transactiondb_t
+	name      string
+	opts      *Options
+	txnDbOpts *TransactionDBOptions
+}
+
+// OpenTransactionDb opens a database supporting transactions with the
+// specified options.
+func OpenTransactionDb(opts *Options, txnDbOpts *TransactionDBOptions, name string) (*TransactionDB, error) {
+	var (
+		cErr  *C.char
+		cName = C.CString(name)
+	)
+	defer C.free(unsafe.Pointer(cName))
+	db := C.rocksdb_transactiondb_open(opts.c, txnDbOpts.c, cName, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, newError(C.GoString(cErr))
+	}
+	return &TransactionDB{
+		name:      name,
+		c:         db,
+		opts:      opts,
+		txnDbOpts: txnDbOpts,
+	}, nil
+}
+
+// Name returns the name of the database.
+func (db *TransactionDB) Name() string {
+	return db.name
+}
+
+// TransactionBegin begins a new transaction. The oldTxn transaction is
+// reused if given, it has to be committed or rolled back. The snapshots
+// returned by its GetSnapshot are invalid after the reuse.
+func (db *TransactionDB) TransactionBegin(opts *WriteOptions, txnOpts *TransactionOptions, oldTxn *Transaction) *Transaction {
+	if oldTxn != nil {
+		oldTxn.freeSnapshots()
+		oldTxn.c = C.rocksdb_transaction_begin(db.c, opts.c, txnOpts.c, oldTxn.c)
+		return oldTxn
+	}
+	return NewNativeTransaction(C.rocksdb_transaction_begin(db.c, opts.c, txnOpts.c, nil))
+}
+
+// Get returns the data associated with the key from the database.
+func (db *TransactionDB) Get(opts *ReadOptions, key []byte) (*Slice, error) {
+	var (
+		cErr    *C.char
+		cValLen C.size_t
+		cKey    = byteToChar(key)
+	)
+	cValue := C.rocksdb_transactiondb_get(db.c, opts.c, cKey, C.size_t(len(key)), &cValLen, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, newError(C.GoString(cErr))
+	}
+	return NewSlice(cValue, cValLen), nil
+}
+
+// Put writes data associated with a key to the database, the key is locked
+// like by a transaction of its own.
+func (db *TransactionDB) Put(opts *WriteOptions, key, value []byte) error {
+	var (
+		cErr   *C.char
+		cKey   = byteToChar(key)
+		cValue = byteToChar(value)
+	)
+	C.rocksdb_transactiondb_put(db.c, opts.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// Delete removes the data associated with the key from the database, the
+// key is locked like by a transaction of its own.
+func (db *TransactionDB) Delete(opts *WriteOptions, key []byte) error {
+	var (
+		cErr *C.char
+		cKey = byteToChar(key)
+	)
+	C.rocksdb_transactiondb_delete(db.c, opts.c, cKey, C.size_t(len(key)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// Merge merges the data associated with the key with the actual data in the
+// database, the key is locked like by a transaction of its own.
+func (db *TransactionDB) Merge(opts *WriteOptions, key []byte, value []byte) error {
+	var (
+		cErr   *C.char
+		cKey   = byteToChar(key)
+		cValue = byteToChar(value)
+	)
+	C.rocksdb_transactiondb_merge(db.c, opts.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)), &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// Write writes a WriteBatch to the database, its keys are locked like by a
+// transaction of its own.
+func (db *TransactionDB) Write(opts *WriteOptions, batch *WriteBatch) error {
+	var cErr *C.char
+	C.rocksdb_transactiondb_write(db.c, opts.c, batch.c, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// NewIterator returns an Iterator over the the database that uses the
+// ReadOptions given.
+func (db *TransactionDB) NewIterator(opts *ReadOptions) *Iterator {
+	cIter := C.rocksdb_transactiondb_create_iterator(db.c, opts.c)
+	return NewNativeIterator(unsafe.Pointer(cIter))
+}
+
+// Close closes the database.
+func (db *TransactionDB) Close() {
+	C.rocksdb_transactiondb_close(db.c)
+	db.c = nil
+}
@@ -0,0 +1,178 @@
+package rdb
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestTransactionDBCommitRollback(t *testing.T) {
+	db := newTestTransactionDB(t, "TestTransactionDBCommitRollback")
+	defer db.Close()
+
+	var (
+		wo      = NewDefaultWriteOptions()
+		ro      = NewDefaultReadOptions()
+		txnOpts = NewDefaultTransactionOptions()
+	)
+	ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("val1")))
+
+	txn := db.TransactionBegin(wo, txnOpts, nil)
+	defer txn.Destroy()
+	ensure.Nil(t, txn.Put([]byte("key2"), []byte("val2")))
+	ensure.Nil(t, txn.Delete([]byte("key1")))
+
+	// uncommitted changes are only visible in the transaction
+	v, err := txn.Get(ro, []byte("key2"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v.Data(), []byte("val2"))
+	v, err = db.Get(ro, []byte("key2"))
+	ensure.Nil(t, err)
+	ensure.True(t, v.Data() == nil)
+
+	txn.SetSavePoint()
+	ensure.Nil(t, txn.Put([]byte("key3"), []byte("val3")))
+	ensure.Nil(t, txn.RollbackToSavePoint())
+	ensure.True(t, errors.Is(txn.RollbackToSavePoint(), ErrNotFound))
+
+	ensure.Nil(t, txn.Commit())
+	v, err = db.Get(ro, []byte("key1"))
+	ensure.Nil(t, err)
+	ensure.True(t, v.Data() == nil)
+	v, err = db.Get(ro, []byte("key2"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v.Data(), []byte("val2"))
+	v, err = db.Get(ro, []byte("key3"))
+	ensure.Nil(t, err)
+	ensure.True(t, v.Data() == nil)
+
+	txn = db.TransactionBegin(wo, txnOpts, txn)
+	ensure.Nil(t, txn.Put([]byte("key4"), []byte("val4")))
+	ensure.Nil(t, txn.Rollback())
+	v, err = db.Get(ro, []byte("key4"))
+	ensure.Nil(t, err)
+	ensure.True(t, v.Data() == nil)
+}
+
+func TestTransactionDBLockTimeout(t *testing.T) {
+	db := newTestTransactionDB(t, "TestTransactionDBLockTimeout")
+	defer db.Close()
+
+	var (
+		wo      = NewDefaultWriteOptions()
+		ro      = NewDefaultReadOptions()
+		txnOpts = NewDefaultTransactionOptions()
+	)
+	txnOpts.SetLockTimeout(10)
+	txn1 := db.TransactionBegin(wo, txnOpts, nil)
+	defer txn1.Destroy()
+	txn2 := db.TransactionBegin(wo, txnOpts, nil)
+	defer txn2.Destroy()
+
+	_, err := txn1.GetForUpdate(ro, []byte("key"), true)
+	ensure.Nil(t, err)
+	err = txn2.Put([]byte("key"), []byte("val"))
+	ensure.True(t, errors.Is(err, ErrTimedOut), err)
+	ensure.True(t, errors.Is(err, ErrLockTimeout), err)
+
+	ensure.Nil(t, txn1.Commit())
+	ensure.Nil(t, txn2.Put([]byte("key"), []byte("val")))
+	ensure.Nil(t, txn2.Commit())
+}
+
+func TestTransactionDBDeadlock(t *testing.T) {
+	db := newTestTransactionDB(t, "TestTransactionDBDeadlock")
+	defer db.Close()
+
+	var (
+		wo      = NewDefaultWriteOptions()
+		ro      = NewDefaultReadOptions()
+		txnOpts = NewDefaultTransactionOptions()
+	)
+	txnOpts.SetDeadlockDetect(true)
+	txnOpts.SetLockTimeout(10000)
+	txn1 := db.TransactionBegin(wo, txnOpts, nil)
+	defer txn1.Destroy()
+	txn2 := db.TransactionBegin(wo, txnOpts, nil)
+	defer txn2.Destroy()
+
+	_, err := txn1.GetForUpdate(ro, []byte("a"), true)
+	ensure.Nil(t, err)
+	_, err = txn2.GetForUpdate(ro, []byte("b"), true)
+	ensure.Nil(t, err)
+
+	// both wait for the key of the other, one is chosen to break the cycle
+	errs := make(chan error, 2)
+	lock := func(txn *Transaction, key string) {
+		_, err := txn.GetForUpdate(ro, []byte(key), true)
+		if err != nil {
+			txn.Rollback()
+		}
+		errs <- err
+	}
+	go lock(txn1, "b")
+	go lock(txn2, "a")
+	err1, err2 := <-errs, <-errs
+	if err1 == nil {
+		err1, err2 = err2, err1
+	}
+	ensure.Nil(t, err2)
+	ensure.True(t, errors.Is(err1, ErrBusy), err1)
+	ensure.True(t, errors.Is(err1, ErrDeadlock), err1)
+}
+
+func newTestTransactionDB(t *testing.T, name string) *TransactionDB {
+	dir, err := ioutil.TempDir("", "gorocksdb-"+name)
+	ensure.Nil(t, err)
+
+	opts := NewDefaultOptions()
+	opts.SetCreateIfMissing(true)
+	db, err := OpenTransactionDb(opts, NewDefaultTransactionDBOptions(), dir)
+	ensure.Nil(t, err)
+	return db
+}
+
+func TestTransactionGetSnapshot(t *testing.T) {
+	db := newTestTransactionDB(t, "TestTransactionGetSnapshot")
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	txnOpts := NewDefaultTransactionOptions()
+	defer txnOpts.Destroy()
+	ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("val1")))
+
+	txn := db.TransactionBegin(wo, txnOpts, nil)
+	ensure.True(t, txn.GetSnapshot() == nil)
+	txn.Destroy()
+
+	txnOpts.SetSetSnapshot(true)
+	txn = db.TransactionBegin(wo, txnOpts, nil)
+	defer txn.Destroy()
+	snap := txn.GetSnapshot()
+	ensure.NotNil(t, snap)
+	ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("val2")))
+
+	ro := NewDefaultReadOptions()
+	defer ro.Destroy()
+	ro.SetSnapshot(snap)
+	v, err := db.Get(ro, []byte("key1"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v.Data(), []byte("val1"))
+	v.Free()
+	// the transaction owns the snapshot
+	snap.Release()
+
+	// reusing the transaction takes a new snapshot
+	ensure.Nil(t, txn.Commit())
+	txn = db.TransactionBegin(wo, txnOpts, txn)
+	snap = txn.GetSnapshot()
+	ensure.NotNil(t, snap)
+	ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("val3")))
+	ro.SetSnapshot(snap)
+	v, err = db.Get(ro, []byte("key1"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v.Data(), []byte("val2"))
+	v.Free()
+}
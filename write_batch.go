@@ -2,7 +2,12 @@ package rdb
 
 // #include "rocksdb/c.h"
 import "C"
-import "io"
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
 
 // WriteBatch is a batching of Puts, Merges and Deletes.
 type WriteBatch struct {
@@ -65,6 +70,36 @@ func (wb *WriteBatch) DeleteCF(cf *ColumnFamilyHandle, key []byte) {
 	C.rocksdb_writebatch_delete_cf(wb.c, cf.c, cKey, C.size_t(len(key)))
 }
 
+// SingleDelete queues a single deletion of the data at key, see
+// DB.SingleDelete.
+func (wb *WriteBatch) SingleDelete(key []byte) {
+	cKey := byteToChar(key)
+	C.rocksdb_writebatch_singledelete(wb.c, cKey, C.size_t(len(key)))
+}
+
+// SingleDeleteCF queues a single deletion of the data at key in a column
+// family, see DB.SingleDelete.
+func (wb *WriteBatch) SingleDeleteCF(cf *ColumnFamilyHandle, key []byte) {
+	cKey := byteToChar(key)
+	C.rocksdb_writebatch_singledelete_cf(wb.c, cf.c, cKey, C.size_t(len(key)))
+}
+
+// DeleteRange queues a deletion of the data of all the keys in the range
+// [startKey, endKey).
+func (wb *WriteBatch) DeleteRange(startKey, endKey []byte) {
+	cStartKey := byteToChar(startKey)
+	cEndKey := byteToChar(endKey)
+	C.rocksdb_writebatch_delete_range(wb.c, cStartKey, C.size_t(len(startKey)), cEndKey, C.size_t(len(endKey)))
+}
+
+// DeleteRangeCF queues a deletion of the data of all the keys in the range
+// [startKey, endKey) in a column family.
+func (wb *WriteBatch) DeleteRangeCF(cf *ColumnFamilyHandle, startKey, endKey []byte) {
+	cStartKey := byteToChar(startKey)
+	cEndKey := byteToChar(endKey)
+	C.rocksdb_writebatch_delete_range_cf(wb.c, cf.c, cStartKey, C.size_t(len(startKey)), cEndKey, C.size_t(len(endKey)))
+}
+
 // Data returns the serialized version of this batch.
 func (wb *WriteBatch) Data() []byte {
 	var cSize C.size_t
@@ -79,11 +114,7 @@ func (wb *WriteBatch) Count() int {
 
 // NewIterator returns a iterator to iterate over the records in the batch.
 func (wb *WriteBatch) NewIterator() *WriteBatchIterator {
-	data := wb.Data()
-	if len(data) < 8+4 {
-		return &WriteBatchIterator{}
-	}
-	return &WriteBatchIterator{data: data[12:]}
+	return newWriteBatchIterator(wb.Data())
 }
 
 // Clear removes all the enqueued Put and Deletes.
@@ -100,26 +131,96 @@ func (wb *WriteBatch) Destroy() {
 // WriteBatchRecordType describes the type of a batch record.
 type WriteBatchRecordType byte
 
-// Types of batch records.
+// Types of batch records, they are the tags of the records in the serialized
+// batch.
 const (
 	WriteBatchRecordTypeDeletion WriteBatchRecordType = 0x0
 	WriteBatchRecordTypeValue    WriteBatchRecordType = 0x1
 	WriteBatchRecordTypeMerge    WriteBatchRecordType = 0x2
 	WriteBatchRecordTypeLogData  WriteBatchRecordType = 0x3
+
+	WriteBatchRecordTypeColumnFamilyDeletion       WriteBatchRecordType = 0x4
+	WriteBatchRecordTypeColumnFamilyValue          WriteBatchRecordType = 0x5
+	WriteBatchRecordTypeColumnFamilyMerge          WriteBatchRecordType = 0x6
+	WriteBatchRecordTypeSingleDeletion             WriteBatchRecordType = 0x7
+	WriteBatchRecordTypeColumnFamilySingleDeletion WriteBatchRecordType = 0x8
+	WriteBatchRecordTypeBeginPrepareXID            WriteBatchRecordType = 0x9
+	WriteBatchRecordTypeEndPrepareXID              WriteBatchRecordType = 0xA
+	WriteBatchRecordTypeCommitXID                  WriteBatchRecordType = 0xB
+	WriteBatchRecordTypeRollbackXID                WriteBatchRecordType = 0xC
+	WriteBatchRecordTypeNoop                       WriteBatchRecordType = 0xD
+	WriteBatchRecordTypeColumnFamilyRangeDeletion  WriteBatchRecordType = 0xE
+	WriteBatchRecordTypeRangeDeletion              WriteBatchRecordType = 0xF
+	WriteBatchRecordTypeColumnFamilyBlobIndex      WriteBatchRecordType = 0x10
+	WriteBatchRecordTypeBlobIndex                  WriteBatchRecordType = 0x11
+	WriteBatchRecordTypeBeginPersistedPrepareXID   WriteBatchRecordType = 0x12
+	WriteBatchRecordTypeBeginUnprepareXID          WriteBatchRecordType = 0x13
 )
 
+// writeBatchHeaderSize is the size of the header of a serialized batch, the
+// sequence number (fixed64) followed by the count of records (fixed32).
+const writeBatchHeaderSize = 8 + 4
+
+// columnFamilyRecordTypes maps the column family scoped record types to the
+// types of the default column family.
+var columnFamilyRecordTypes = map[WriteBatchRecordType]WriteBatchRecordType{
+	WriteBatchRecordTypeColumnFamilyDeletion:       WriteBatchRecordTypeDeletion,
+	WriteBatchRecordTypeColumnFamilyValue:          WriteBatchRecordTypeValue,
+	WriteBatchRecordTypeColumnFamilyMerge:          WriteBatchRecordTypeMerge,
+	WriteBatchRecordTypeColumnFamilySingleDeletion: WriteBatchRecordTypeSingleDeletion,
+	WriteBatchRecordTypeColumnFamilyRangeDeletion:  WriteBatchRecordTypeRangeDeletion,
+	WriteBatchRecordTypeColumnFamilyBlobIndex:      WriteBatchRecordTypeBlobIndex,
+}
+
 // WriteBatchRecord represents a record inside a WriteBatch.
+//
+// Records of column families have the type of the record in the default
+// column family and the id of the column family in ColumnFamilyID. Range
+// deletions hold the start key in Key and the end key in Value, log data
+// holds the blob in Key and the XID markers hold the transaction id in Key.
 type WriteBatchRecord struct {
-	Key   []byte
-	Value []byte
-	Type  WriteBatchRecordType
+	ColumnFamilyID uint32
+	Key            []byte
+	Value          []byte
+	Type           WriteBatchRecordType
 }
 
 // WriteBatchIterator represents a iterator to iterator over records.
 type WriteBatchIterator struct {
-	data   []byte
-	record WriteBatchRecord
-	err    error
+	data     []byte
+	sequence uint64
+	count    int
+	record   WriteBatchRecord
+	err      error
+}
+
+// newWriteBatchIterator returns a iterator over the records of the
+// serialized batch, the header is decoded right away.
+func newWriteBatchIterator(data []byte) *WriteBatchIterator {
+	iter := &WriteBatchIterator{}
+	if len(data) == 0 {
+		return iter
+	}
+	if len(data) < writeBatchHeaderSize {
+		iter.err = fmt.Errorf("WriteBatch header too short: %d bytes", len(data))
+		return iter
+	}
+	iter.sequence = binary.LittleEndian.Uint64(data)
+	iter.count = int(binary.LittleEndian.Uint32(data[8:]))
+	iter.data = data[writeBatchHeaderSize:]
+	return iter
+}
+
+// Sequence returns the sequence number of the batch, the one of its first
+// record. It's 0 unless the batch was written or read from the logs.
+func (iter *WriteBatchIterator) Sequence() uint64 {
+	return iter.sequence
+}
+
+// Count returns the number of updates in the batch as recorded in its
+// header. Log data and XID markers aren't counted.
+func (iter *WriteBatchIterator) Count() int {
+	return iter.count
 }
 
 // Next returns the next record.
@@ -129,36 +230,42 @@ func (iter *WriteBatchIterator) Next() bool {
 		return false
 	}
 	// reset the current record
-	iter.record.Key = nil
-	iter.record.Value = nil
+	iter.record = WriteBatchRecord{}
 
 	// parse the record type
 	recordType := WriteBatchRecordType(iter.data[0])
-	iter.record.Type = recordType
 	iter.data = iter.data[1:]
 
-	// parse the key
-	x, n := iter.decodeVarint(iter.data)
-	if n == 0 {
-		iter.err = io.ErrShortBuffer
-		return false
-	}
-	k := n + int(x)
-	iter.record.Key = iter.data[n:k]
-	iter.data = iter.data[k:]
-
-	// parse the data
-	if recordType == WriteBatchRecordTypeValue || recordType == WriteBatchRecordTypeMerge {
+	// parse the column family
+	if t, ok := columnFamilyRecordTypes[recordType]; ok {
 		x, n := iter.decodeVarint(iter.data)
-		if n == 0 {
+		if n == 0 || x > math.MaxUint32 {
 			iter.err = io.ErrShortBuffer
 			return false
 		}
-		k := n + int(x)
-		iter.record.Value = iter.data[n:k]
-		iter.data = iter.data[k:]
+		iter.record.ColumnFamilyID = uint32(x)
+		iter.data = iter.data[n:]
+		recordType = t
 	}
-	return true
+	iter.record.Type = recordType
+
+	var ok bool
+	switch recordType {
+	case WriteBatchRecordTypeDeletion, WriteBatchRecordTypeSingleDeletion, WriteBatchRecordTypeLogData,
+		WriteBatchRecordTypeEndPrepareXID, WriteBatchRecordTypeCommitXID, WriteBatchRecordTypeRollbackXID:
+		iter.record.Key, ok = iter.decodeSlice()
+	case WriteBatchRecordTypeValue, WriteBatchRecordTypeMerge, WriteBatchRecordTypeRangeDeletion,
+		WriteBatchRecordTypeBlobIndex:
+		if iter.record.Key, ok = iter.decodeSlice(); ok {
+			iter.record.Value, ok = iter.decodeSlice()
+		}
+	case WriteBatchRecordTypeBeginPrepareXID, WriteBatchRecordTypeBeginPersistedPrepareXID,
+		WriteBatchRecordTypeBeginUnprepareXID, WriteBatchRecordTypeNoop:
+		ok = true
+	default:
+		iter.err = fmt.Errorf("Unknown WriteBatch record tag 0x%x", byte(recordType))
+	}
+	return ok
 }
 
 // Record returns the current record.
@@ -171,6 +278,19 @@ func (iter *WriteBatchIterator) Error() error {
 	return iter.err
 }
 
+// decodeSlice parses a length prefixed slice.
+func (iter *WriteBatchIterator) decodeSlice() ([]byte, bool) {
+	x, n := iter.decodeVarint(iter.data)
+	if n == 0 || x > uint64(len(iter.data)-n) {
+		iter.err = io.ErrShortBuffer
+		return nil, false
+	}
+	k := n + int(x)
+	b := iter.data[n:k]
+	iter.data = iter.data[k:]
+	return b, true
+}
+
 func (iter *WriteBatchIterator) decodeVarint(buf []byte) (x uint64, n int) {
 	// x, n already 0
 	for shift := uint(0); shift < 64; shift += 7 {
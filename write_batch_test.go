@@ -1,6 +1,7 @@
 package rdb
 
 import (
+	"io"
 	"testing"
 
 	"github.com/facebookgo/ensure"
@@ -73,3 +74,134 @@ func TestWriteBatchIterator(t *testing.T) {
 	// there shouldn't be any left
 	ensure.False(t, iter.Next())
 }
+
+func TestWriteBatchDeleteRange(t *testing.T) {
+	db := newTestDB(t, "TestWriteBatchDeleteRange", nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("val1")))
+	ensure.Nil(t, db.Put(wo, []byte("key2"), []byte("val2")))
+
+	wb := NewWriteBatch()
+	defer wb.Destroy()
+	wb.DeleteRange([]byte("key1"), []byte("key2"))
+
+	iter := wb.NewIterator()
+	ensure.True(t, iter.Next())
+	record := iter.Record()
+	ensure.DeepEqual(t, record.Type, WriteBatchRecordTypeRangeDeletion)
+	ensure.DeepEqual(t, record.Key, []byte("key1"))
+	ensure.DeepEqual(t, record.Value, []byte("key2"))
+	ensure.False(t, iter.Next())
+	ensure.Nil(t, iter.Error())
+
+	ensure.Nil(t, db.Write(wo, wb))
+	ro := NewDefaultReadOptions()
+	v1, err := db.GetBytes(ro, []byte("key1"))
+	ensure.Nil(t, err)
+	ensure.True(t, v1 == nil)
+	v2, err := db.GetBytes(ro, []byte("key2"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v2, []byte("val2"))
+}
+
+func TestWriteBatchSingleDelete(t *testing.T) {
+	db := newTestDB(t, "TestWriteBatchSingleDelete", nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	ro := NewDefaultReadOptions()
+	ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("val1")))
+	ensure.Nil(t, db.Put(wo, []byte("key2"), []byte("val2")))
+
+	wb := NewWriteBatch()
+	defer wb.Destroy()
+	wb.SingleDelete([]byte("key1"))
+
+	iter := wb.NewIterator()
+	ensure.True(t, iter.Next())
+	record := iter.Record()
+	ensure.DeepEqual(t, record.Type, WriteBatchRecordTypeSingleDeletion)
+	ensure.DeepEqual(t, record.Key, []byte("key1"))
+	ensure.True(t, record.Value == nil)
+	ensure.False(t, iter.Next())
+
+	ensure.Nil(t, db.Write(wo, wb))
+	v1, err := db.GetBytes(ro, []byte("key1"))
+	ensure.Nil(t, err)
+	ensure.True(t, v1 == nil)
+
+	ensure.Nil(t, db.SingleDelete(wo, []byte("key2")))
+	v2, err := db.GetBytes(ro, []byte("key2"))
+	ensure.Nil(t, err)
+	ensure.True(t, v2 == nil)
+}
+
+func TestWriteBatchIteratorColumnFamilies(t *testing.T) {
+	db := newTestDB(t, "TestWriteBatchIteratorColumnFamilies", func(opts *Options) {
+		opts.SetWALTtlSeconds(3600)
+	})
+	defer db.Close()
+	cf, err := db.CreateColumnFamily(NewDefaultOptions(), "guide")
+	ensure.Nil(t, err)
+	defer cf.Destroy()
+
+	wb := NewWriteBatch()
+	defer wb.Destroy()
+	wb.Put([]byte("key0"), []byte("val0"))
+	wb.PutCF(cf, []byte("key1"), []byte("val1"))
+	wb.MergeCF(cf, []byte("key2"), []byte("val2"))
+	wb.DeleteCF(cf, []byte("key3"))
+	wb.SingleDeleteCF(cf, []byte("key4"))
+	wb.DeleteRangeCF(cf, []byte("key5"), []byte("key6"))
+
+	expected := []WriteBatchRecord{
+		{0, []byte("key0"), []byte("val0"), WriteBatchRecordTypeValue},
+		{1, []byte("key1"), []byte("val1"), WriteBatchRecordTypeValue},
+		{1, []byte("key2"), []byte("val2"), WriteBatchRecordTypeMerge},
+		{1, []byte("key3"), nil, WriteBatchRecordTypeDeletion},
+		{1, []byte("key4"), nil, WriteBatchRecordTypeSingleDeletion},
+		{1, []byte("key5"), []byte("key6"), WriteBatchRecordTypeRangeDeletion},
+	}
+	iter := wb.NewIterator()
+	ensure.DeepEqual(t, iter.Count(), len(expected))
+	ensure.DeepEqual(t, iter.Sequence(), uint64(0))
+	for _, r := range expected {
+		ensure.True(t, iter.Next())
+		ensure.DeepEqual(t, *iter.Record(), r)
+	}
+	ensure.False(t, iter.Next())
+	ensure.Nil(t, iter.Error())
+
+	// the sequence number is assigned when the batch is written
+	ensure.Nil(t, db.Put(NewDefaultWriteOptions(), []byte("key"), []byte("val")))
+	ensure.Nil(t, db.Write(NewDefaultWriteOptions(), wb))
+	logIter, err := db.GetUpdatesSince(2)
+	ensure.Nil(t, err)
+	defer logIter.Close()
+	ensure.True(t, logIter.Valid())
+	logged, seq := logIter.GetBatch()
+	defer logged.Destroy()
+	iter = logged.NewIterator()
+	ensure.DeepEqual(t, iter.Sequence(), seq)
+	ensure.DeepEqual(t, iter.Sequence(), uint64(2))
+	ensure.DeepEqual(t, iter.Count(), len(expected))
+}
+
+func TestWriteBatchIteratorErrors(t *testing.T) {
+	iter := newWriteBatchIterator([]byte{1, 0, 0})
+	ensure.False(t, iter.Next())
+	ensure.NotNil(t, iter.Error())
+
+	header := []byte{7, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0}
+	iter = newWriteBatchIterator(append(header, 0x42, 1, 'a'))
+	ensure.DeepEqual(t, iter.Sequence(), uint64(7))
+	ensure.DeepEqual(t, iter.Count(), 1)
+	ensure.False(t, iter.Next())
+	ensure.DeepEqual(t, iter.Error().Error(), "Unknown WriteBatch record tag 0x42")
+
+	iter = newWriteBatchIterator(append(header, byte(WriteBatchRecordTypeValue), 5, 'a'))
+	ensure.False(t, iter.Next())
+	ensure.DeepEqual(t, iter.Error(), io.ErrShortBuffer)
+}
@@ -0,0 +1,154 @@
+package rdb
+
+// #include "rocksdb/c.h"
+import "C"
+import "unsafe"
+
+// WriteBatchWithIndex is a WriteBatch with a searchable index, so the
+// batched records can be read back before the batch is written.
+type WriteBatchWithIndex struct {
+	c *C.rocksdb_writebatch_wi_t
+}
+
+// NewWriteBatchWithIndex creates a WriteBatchWithIndex object. If
+// overwriteKey is set, the index keeps only the last record of every key
+// and iterators see only that one.
+func NewWriteBatchWithIndex(reservedBytes int, overwriteKey bool) *WriteBatchWithIndex {
+	return NewNativeWriteBatchWithIndex(C.rocksdb_writebatch_wi_create(C.size_t(reservedBytes), boolToChar(overwriteKey)))
+}
+
+// NewNativeWriteBatchWithIndex creates a WriteBatchWithIndex object.
+func NewNativeWriteBatchWithIndex(c *C.rocksdb_writebatch_wi_t) *WriteBatchWithIndex {
+	return &WriteBatchWithIndex{c}
+}
+
+// Put queues a key-value pair.
+func (wb *WriteBatchWithIndex) Put(key, value []byte) {
+	cKey := byteToChar(key)
+	cValue := byteToChar(value)
+	C.rocksdb_writebatch_wi_put(wb.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)))
+}
+
+// PutCF queues a key-value pair in a column family.
+func (wb *WriteBatchWithIndex) PutCF(cf *ColumnFamilyHandle, key, value []byte) {
+	cKey := byteToChar(key)
+	cValue := byteToChar(value)
+	C.rocksdb_writebatch_wi_put_cf(wb.c, cf.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)))
+}
+
+// Merge queues a merge of "value" with the existing value of "key".
+func (wb *WriteBatchWithIndex) Merge(key, value []byte) {
+	cKey := byteToChar(key)
+	cValue := byteToChar(value)
+	C.rocksdb_writebatch_wi_merge(wb.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)))
+}
+
+// MergeCF queues a merge of "value" with the existing value of "key" in a
+// column family.
+func (wb *WriteBatchWithIndex) MergeCF(cf *ColumnFamilyHandle, key, value []byte) {
+	cKey := byteToChar(key)
+	cValue := byteToChar(value)
+	C.rocksdb_writebatch_wi_merge_cf(wb.c, cf.c, cKey, C.size_t(len(key)), cValue, C.size_t(len(value)))
+}
+
+// Delete queues a deletion of the data at key.
+func (wb *WriteBatchWithIndex) Delete(key []byte) {
+	cKey := byteToChar(key)
+	C.rocksdb_writebatch_wi_delete(wb.c, cKey, C.size_t(len(key)))
+}
+
+// DeleteCF queues a deletion of the data at key in a column family.
+func (wb *WriteBatchWithIndex) DeleteCF(cf *ColumnFamilyHandle, key []byte) {
+	cKey := byteToChar(key)
+	C.rocksdb_writebatch_wi_delete_cf(wb.c, cf.c, cKey, C.size_t(len(key)))
+}
+
+// SingleDelete queues a single deletion of the data at key, see
+// DB.SingleDelete.
+func (wb *WriteBatchWithIndex) SingleDelete(key []byte) {
+	cKey := byteToChar(key)
+	C.rocksdb_writebatch_wi_singledelete(wb.c, cKey, C.size_t(len(key)))
+}
+
+// GetFromBatch returns the data associated with the key from the batch
+// only. The options provide the merge operator for merged keys.
+func (wb *WriteBatchWithIndex) GetFromBatch(opts *Options, key []byte) (*Slice, error) {
+	var (
+		cErr    *C.char
+		cValLen C.size_t
+		cKey    = byteToChar(key)
+	)
+	cValue := C.rocksdb_writebatch_wi_get_from_batch(wb.c, opts.c, cKey, C.size_t(len(key)), &cValLen, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, newError(C.GoString(cErr))
+	}
+	return NewSlice(cValue, cValLen), nil
+}
+
+// GetFromBatchAndDB returns the data associated with the key from the batch
+// applied on top of the database.
+func (wb *WriteBatchWithIndex) GetFromBatchAndDB(db *DB, opts *ReadOptions, key []byte) (*Slice, error) {
+	var (
+		cErr    *C.char
+		cValLen C.size_t
+		cKey    = byteToChar(key)
+	)
+	cValue := C.rocksdb_writebatch_wi_get_from_batch_and_db(wb.c, db.c, opts.c, cKey, C.size_t(len(key)), &cValLen, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return nil, newError(C.GoString(cErr))
+	}
+	return NewSlice(cValue, cValLen), nil
+}
+
+// NewIteratorWithBase returns an Iterator over the base iterator with the
+// batch applied on top of it. The base iterator is owned by the returned
+// Iterator and must not be used or closed anymore. The batch must be
+// created with overwriteKey set, see NewWriteBatchWithIndex.
+func (wb *WriteBatchWithIndex) NewIteratorWithBase(base *Iterator) *Iterator {
+	cIter := C.rocksdb_writebatch_wi_create_iterator_with_base(wb.c, base.c)
+	base.c = nil
+	return NewNativeIterator(unsafe.Pointer(cIter))
+}
+
+// Data returns the serialized version of this batch.
+func (wb *WriteBatchWithIndex) Data() []byte {
+	var cSize C.size_t
+	cValue := C.rocksdb_writebatch_wi_data(wb.c, &cSize)
+	return charToByte(cValue, cSize)
+}
+
+// Count returns the number of updates in the batch.
+func (wb *WriteBatchWithIndex) Count() int {
+	return int(C.rocksdb_writebatch_wi_count(wb.c))
+}
+
+// SetSavePoint records the state of the batch, RollbackToSavePoint returns
+// to it.
+func (wb *WriteBatchWithIndex) SetSavePoint() {
+	C.rocksdb_writebatch_wi_set_save_point(wb.c)
+}
+
+// RollbackToSavePoint removes the records queued since the last
+// SetSavePoint and removes the save point.
+func (wb *WriteBatchWithIndex) RollbackToSavePoint() error {
+	var cErr *C.char
+	C.rocksdb_writebatch_wi_rollback_to_save_point(wb.c, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return newError(C.GoString(cErr))
+	}
+	return nil
+}
+
+// Clear removes all the enqueued records.
+func (wb *WriteBatchWithIndex) Clear() {
+	C.rocksdb_writebatch_wi_clear(wb.c)
+}
+
+// Destroy deallocates the WriteBatchWithIndex object.
+func (wb *WriteBatchWithIndex) Destroy() {
+	C.rocksdb_writebatch_wi_destroy(wb.c)
+	wb.c = nil
+}
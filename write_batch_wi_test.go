@@ -0,0 +1,63 @@
+package rdb
+
+import (
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestWriteBatchWithIndex(t *testing.T) {
+	db := newTestDB(t, "TestWriteBatchWithIndex", nil)
+	defer db.Close()
+
+	var (
+		wo = NewDefaultWriteOptions()
+		ro = NewDefaultReadOptions()
+	)
+	ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("db1")))
+	ensure.Nil(t, db.Put(wo, []byte("key3"), []byte("db3")))
+
+	wb := NewWriteBatchWithIndex(0, true)
+	defer wb.Destroy()
+	wb.Put([]byte("key2"), []byte("wb2"))
+	wb.Delete([]byte("key3"))
+	ensure.DeepEqual(t, wb.Count(), 2)
+
+	v, err := wb.GetFromBatch(db.opts, []byte("key2"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v.Data(), []byte("wb2"))
+	v, err = wb.GetFromBatch(db.opts, []byte("key1"))
+	ensure.Nil(t, err)
+	ensure.True(t, v.Data() == nil)
+
+	v, err = wb.GetFromBatchAndDB(db, ro, []byte("key1"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v.Data(), []byte("db1"))
+	v, err = wb.GetFromBatchAndDB(db, ro, []byte("key3"))
+	ensure.Nil(t, err)
+	ensure.True(t, v.Data() == nil)
+
+	wb.SetSavePoint()
+	wb.Put([]byte("key4"), []byte("wb4"))
+	ensure.Nil(t, wb.RollbackToSavePoint())
+	ensure.DeepEqual(t, wb.Count(), 2)
+
+	iter := wb.NewIteratorWithBase(db.NewIterator(ro))
+	var keys, values []string
+	for iter.SeekToFirst(); iter.Valid(); iter.Next() {
+		keys = append(keys, string(iter.Key()))
+		values = append(values, string(iter.Value()))
+	}
+	ensure.Nil(t, iter.Err())
+	iter.Close()
+	ensure.DeepEqual(t, keys, []string{"key1", "key2"})
+	ensure.DeepEqual(t, values, []string{"db1", "wb2"})
+
+	ensure.Nil(t, db.WriteWithIndex(wo, wb))
+	v2, err := db.GetBytes(ro, []byte("key2"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v2, []byte("wb2"))
+	v3, err := db.GetBytes(ro, []byte("key3"))
+	ensure.Nil(t, err)
+	ensure.True(t, v3 == nil)
+}
@@ -0,0 +1,52 @@
+package rdb
+
+// #include "rocksdb/c.h"
+import "C"
+
+// WriteBufferManager limits the total memory used by memtables of all the
+// databases and column families it's shared with.
+type WriteBufferManager struct {
+	c *C.rocksdb_write_buffer_manager_t
+
+	// Hold references for GC.
+	cache *Cache
+}
+
+// NewWriteBufferManager creates a WriteBufferManager limiting the memtables
+// to bufferSize bytes. When allowStall is set, writes are stalled once the
+// memory usage exceeds the limit until flushes free enough memory.
+func NewWriteBufferManager(bufferSize int, allowStall bool) *WriteBufferManager {
+	return NewNativeWriteBufferManager(C.rocksdb_write_buffer_manager_create(C.size_t(bufferSize), C.bool(allowStall)))
+}
+
+// NewWriteBufferManagerWithCache creates a WriteBufferManager which charges
+// the memory used by memtables to the cache, so memtables and cached blocks
+// share a single memory budget.
+func NewWriteBufferManagerWithCache(bufferSize int, cache *Cache, allowStall bool) *WriteBufferManager {
+	wbm := NewNativeWriteBufferManager(C.rocksdb_write_buffer_manager_create_with_cache(C.size_t(bufferSize), cache.c, C.bool(allowStall)))
+	wbm.cache = cache
+	return wbm
+}
+
+// NewNativeWriteBufferManager creates a WriteBufferManager object.
+func NewNativeWriteBufferManager(c *C.rocksdb_write_buffer_manager_t) *WriteBufferManager {
+	return &WriteBufferManager{c: c}
+}
+
+// MemoryUsage returns the memory currently used by memtables.
+func (wbm *WriteBufferManager) MemoryUsage() int {
+	return int(C.rocksdb_write_buffer_manager_memory_usage(wbm.c))
+}
+
+// BufferSize returns the memory limit of the memtables.
+func (wbm *WriteBufferManager) BufferSize() int {
+	return int(C.rocksdb_write_buffer_manager_buffer_size(wbm.c))
+}
+
+// Destroy deallocates the WriteBufferManager object. Databases using it keep
+// their reference until they are closed.
+func (wbm *WriteBufferManager) Destroy() {
+	C.rocksdb_write_buffer_manager_destroy(wbm.c)
+	wbm.c = nil
+	wbm.cache = nil
+}